@@ -0,0 +1,135 @@
+// Package base58 is the Base58 and Base58Check codec shared by keys, addresses and
+// keystores. It lives in 00-encoding, below the numbered lessons that import it.
+package base58
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var ErrInvalidChecksum = errors.New("Invalid checksum: input is mistyped or corrupted")
+
+// decodeMap maps each character to its value in the alphabet, -1 for invalid characters
+var decodeMap = func() [256]int8 {
+	var m [256]int8
+	for i := range m {
+		m[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		m[alphabet[i]] = int8(i)
+	}
+	return m
+}()
+
+// Encode encodes bytes with the Bitcoin alphabet, keeping each leading zero byte as '1'
+func Encode(input []byte) string {
+	zeros := 0
+	for zeros < len(input) && input[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256) / log(58) is below 1.38 digits per byte
+	size := (len(input)-zeros)*138/100 + 1
+	digits := make([]byte, size)
+	length := 0
+	for _, b := range input[zeros:] {
+		carry := uint32(b)
+		i := 0
+		for j := size - 1; (carry != 0 || i < length) && j >= 0; j-- {
+			carry += uint32(digits[j]) << 8
+			digits[j] = byte(carry % 58)
+			carry /= 58
+			i++
+		}
+		length = i
+	}
+
+	start := size - length
+	for start < size && digits[start] == 0 {
+		start++
+	}
+
+	result := make([]byte, zeros+size-start)
+	for i := 0; i < zeros; i++ {
+		result[i] = alphabet[0]
+	}
+	for i, digit := range digits[start:] {
+		result[zeros+i] = alphabet[digit]
+	}
+	return string(result)
+}
+
+// Decode decodes a base58 string, restoring each leading '1' as a zero byte
+func Decode(input string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(input) && input[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	// log(58) / log(256) is below 0.733 bytes per digit
+	size := (len(input)-zeros)*733/1000 + 1
+	decoded := make([]byte, size)
+	length := 0
+	for i := zeros; i < len(input); i++ {
+		value := decodeMap[input[i]]
+		if value < 0 {
+			return nil, fmt.Errorf("Invalid base58 character %q at position %d", input[i], i)
+		}
+
+		carry := uint32(value)
+		j := 0
+		for k := size - 1; (carry != 0 || j < length) && k >= 0; k-- {
+			carry += uint32(decoded[k]) * 58
+			decoded[k] = byte(carry)
+			carry >>= 8
+			j++
+		}
+		length = j
+	}
+
+	start := size - length
+	for start < size && decoded[start] == 0 {
+		start++
+	}
+	return append(make([]byte, zeros, zeros+size-start), decoded[start:]...), nil
+}
+
+// CheckEncode appends the first 4 bytes of double SHA-256 and encodes with base58
+func CheckEncode(payload []byte) string {
+	data := make([]byte, 0, len(payload)+4)
+	data = append(data, payload...)
+	data = append(data, checksum(payload)...)
+	return Encode(data)
+}
+
+// CheckDecode decodes base58 and verifies the checksum
+func CheckDecode(input string) ([]byte, error) {
+	decoded, err := Decode(input)
+	if err != nil {
+		return nil, err
+	}
+	return VerifyChecksum(decoded)
+}
+
+// VerifyChecksum verifies the trailing 4-byte checksum of decoded base58 data and returns the payload
+func VerifyChecksum(decoded []byte) ([]byte, error) {
+	if len(decoded) < 4 {
+		return nil, ErrInvalidChecksum
+	}
+
+	payload := decoded[:len(decoded)-4]
+	if !bytes.Equal(checksum(payload), decoded[len(decoded)-4:]) {
+		return nil, ErrInvalidChecksum
+	}
+	return payload, nil
+}
+
+func checksum(payload []byte) []byte {
+	firstSHA := sha256.Sum256(payload)
+	secondSHA := sha256.Sum256(firstSHA[:])
+	return secondSHA[:4]
+}
@@ -0,0 +1,175 @@
+package base58
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// vectors of Bitcoin Core base58_encode_decode.json
+var vectors = []struct {
+	hex     string
+	encoded string
+}{
+	{"", ""},
+	{"61", "2g"},
+	{"626262", "a3gV"},
+	{"636363", "aPEr"},
+	{"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+	{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+	{"516b6fcd0f", "ABnLTmg"},
+	{"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+	{"572e4794", "3EFU7m"},
+	{"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+	{"10c8511e", "Rt5zm"},
+	{"00000000000000000000", "1111111111"},
+	{"000111d38e5fc9071ffcd20b4a763cc9ae4f252bb4e48fd66a835e252ada93ff480d6dd43dc62a641155a5", "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"},
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, v := range vectors {
+		data, _ := hex.DecodeString(v.hex)
+		if encoded := Encode(data); encoded != v.encoded {
+			t.Fatalf("%s: got %s, want %s", v.hex, encoded, v.encoded)
+		}
+
+		decoded, err := Decode(v.encoded)
+		if err != nil {
+			t.Fatalf("%s: %v", v.encoded, err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Fatalf("%s: got %x, want %s", v.encoded, decoded, v.hex)
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	// 0, O, I and l are excluded from the alphabet
+	tests := []string{"0", "O", "I", "l", "3mJr0", "O3yxU", "3sNI", "4kl8", "s!5<", "t$@mX<*", "1 1", "2g\n", "é"}
+	for _, test := range tests {
+		_, err := Decode(test)
+		if err == nil {
+			t.Fatalf("%q: expected error", test)
+		}
+	}
+}
+
+func TestCheckEncodeDecode(t *testing.T) {
+	// P2PKH address of the hash160 of the compressed public key of private key 1
+	payload, _ := hex.DecodeString("00751e76e8199196d454941c45d1b3a323f1433bd6")
+	encoded := CheckEncode(payload)
+	if encoded != "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH" {
+		t.Fatalf("got %s, want 1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", encoded)
+	}
+
+	decoded, err := CheckDecode(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded, payload) {
+		t.Fatalf("got %x, want %x", decoded, payload)
+	}
+
+	for _, test := range []string{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMJ", "", "1", "2g"} {
+		_, err := CheckDecode(test)
+		if err != ErrInvalidChecksum {
+			t.Fatalf("%q: got %v, want %v", test, err, ErrInvalidChecksum)
+		}
+	}
+}
+
+func TestCompareBigInt(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		data := make([]byte, r.Intn(80))
+		r.Read(data)
+		// leading zero bytes
+		for j := 0; j < len(data) && j < r.Intn(4); j++ {
+			data[j] = 0
+		}
+
+		// the former implementation drops the leading '1' of each zero byte
+		zeros := 0
+		for zeros < len(data) && data[zeros] == 0 {
+			zeros++
+		}
+
+		encoded := Encode(data)
+		if want := strings.Repeat("1", zeros) + bigIntEncode(data); encoded != want {
+			t.Fatalf("%x: got %s, want %s", data, encoded, want)
+		}
+		decoded, err := Decode(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Fatalf("%s: got %x, want %x", encoded, decoded, data)
+		}
+	}
+}
+
+// bigIntEncode is the former big.Int implementation of the address package,
+// unchanged including the dropped leading zero bytes
+func bigIntEncode(input []byte) string {
+	var result []byte
+	num := new(big.Int).SetBytes(input)
+
+	base := big.NewInt(58)
+	zero := big.NewInt(0)
+
+	for num.Cmp(zero) > 0 {
+		mod := new(big.Int)
+		num.DivMod(num, base, mod)
+		result = append([]byte{alphabet[mod.Int64()]}, result...)
+	}
+
+	return string(result)
+}
+
+// bigIntDecode is the former big.Int implementation of the key package
+func bigIntDecode(input string) []byte {
+	num := new(big.Int)
+	base := big.NewInt(58)
+	for _, c := range input {
+		num.Mul(num, base)
+		num.Add(num, big.NewInt(int64(strings.IndexRune(alphabet, c))))
+	}
+
+	zeros := 0
+	for zeros < len(input) && input[zeros] == alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), num.Bytes()...)
+}
+
+// benchmarkAddress has the 25-byte length of a P2PKH address with checksum
+var benchmarkAddress, _ = hex.DecodeString("00751e76e8199196d454941c45d1b3a323f1433bd6ee93c7a1")
+
+func BenchmarkEncode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Encode(benchmarkAddress)
+	}
+}
+
+func BenchmarkEncodeBigInt(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		bigIntEncode(benchmarkAddress)
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	encoded := Encode(benchmarkAddress)
+	for i := 0; i < b.N; i++ {
+		Decode(encoded)
+	}
+}
+
+func BenchmarkDecodeBigInt(b *testing.B) {
+	encoded := Encode(benchmarkAddress)
+	for i := 0; i < b.N; i++ {
+		bigIntDecode(encoded)
+	}
+}
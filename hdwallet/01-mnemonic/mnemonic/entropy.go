@@ -0,0 +1,140 @@
+package mnemonic
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrInsufficientEntropy = errors.New("Insufficient entropy")
+
+// EntropyFromDice converts rolls of a six-sided die into length bits of entropy.
+// The rolls are read as a base-6 number (a roll of 6 counts as 0) and the low
+// length bits of that number are used. Exactly ceil(length/log2(6)) rolls are
+// required, 50 rolls for 128 bits and 100 rolls for 256 bits, so the written-down
+// rolls reproduce the entropy and the reduction discards less than one roll.
+func EntropyFromDice(rolls string, length int) ([]byte, error) {
+	err := ValidateEntropyLength(length)
+	if err != nil {
+		return nil, err
+	}
+
+	rolls = removeSpaces(rolls)
+	required := int(math.Ceil(float64(length) / math.Log2(6)))
+	count := utf8.RuneCountInString(rolls)
+	if count < required {
+		return nil, fmt.Errorf("%w: %d dice rolls provided, but %d bits require %d rolls", ErrInsufficientEntropy, count, length, required)
+	}
+	if count > required {
+		return nil, fmt.Errorf("Invalid dice roll count: %d dice rolls provided, but %d bits require exactly %d rolls", count, length, required)
+	}
+
+	num := new(big.Int)
+	base := big.NewInt(6)
+	for i, roll := range []rune(rolls) {
+		if roll < '1' || roll > '6' {
+			return nil, fmt.Errorf("Invalid dice roll %q at position %d: must be 1 to 6", roll, i+1)
+		}
+		num.Mul(num, base)
+		num.Add(num, big.NewInt(int64((roll-'0')%6)))
+	}
+
+	// keep the low length bits
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(length)), big.NewInt(1))
+	num.And(num, mask)
+	return num.FillBytes(make([]byte, length/8)), nil
+}
+
+// EntropyFromCoinFlips converts coin flips into length bits of entropy.
+// Each flip is 1 or H for heads and 0 or T for tails, and exactly
+// length flips are required.
+func EntropyFromCoinFlips(flips string, length int) ([]byte, error) {
+	err := ValidateEntropyLength(length)
+	if err != nil {
+		return nil, err
+	}
+
+	flips = removeSpaces(strings.ToUpper(flips))
+	count := utf8.RuneCountInString(flips)
+	if count < length {
+		return nil, fmt.Errorf("%w: %d coin flips provided, but %d bits require %d flips", ErrInsufficientEntropy, count, length, length)
+	}
+	if count > length {
+		return nil, fmt.Errorf("Invalid coin flip count: %d coin flips provided, but %d bits require exactly %d flips", count, length, length)
+	}
+
+	sequence := make(bits, length)
+	for i, flip := range []rune(flips) {
+		switch flip {
+		case '1', 'H':
+			sequence[i] = '1'
+		case '0', 'T':
+			sequence[i] = '0'
+		default:
+			return nil, fmt.Errorf("Invalid coin flip %q at position %d: must be 0, 1, H or T", flip, i+1)
+		}
+	}
+	return BitToByte(sequence), nil
+}
+
+// EntropyFromHex decodes raw entropy of 128, 160, 192, 224 or 256 bits from hex
+func EntropyFromHex(hexString string) ([]byte, error) {
+	hexString = removeSpaces(hexString)
+	if strings.HasPrefix(hexString, "0x") || strings.HasPrefix(hexString, "0X") {
+		hexString = hexString[2:]
+	}
+	entropy, err := hex.DecodeString(hexString)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entropy)*8 < 128 {
+		return nil, fmt.Errorf("%w: %d bits provided, but at least 128 bits are required", ErrInsufficientEntropy, len(entropy)*8)
+	}
+	err = ValidateEntropyLength(len(entropy) * 8)
+	if err != nil {
+		return nil, err
+	}
+	return entropy, nil
+}
+
+// NewMnemonicFromDice converts dice rolls to the English mnemonic
+func NewMnemonicFromDice(rolls string, length int) (string, error) {
+	entropy, err := EntropyFromDice(rolls, length)
+	if err != nil {
+		return "", err
+	}
+	return NewMnemonic(entropy)
+}
+
+// NewMnemonicFromCoinFlips converts coin flips to the English mnemonic
+func NewMnemonicFromCoinFlips(flips string, length int) (string, error) {
+	entropy, err := EntropyFromCoinFlips(flips, length)
+	if err != nil {
+		return "", err
+	}
+	return NewMnemonic(entropy)
+}
+
+// NewMnemonicFromHex converts hex entropy to the English mnemonic
+func NewMnemonicFromHex(hexString string) (string, error) {
+	entropy, err := EntropyFromHex(hexString)
+	if err != nil {
+		return "", err
+	}
+	return NewMnemonic(entropy)
+}
+
+func removeSpaces(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
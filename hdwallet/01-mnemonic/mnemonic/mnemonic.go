@@ -3,26 +3,69 @@ package mnemonic
 import (
 	"errors"
 	"strconv"
-	"strings"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/sha512"
 
 	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
 )
 
 type bits []byte
 
+// GenerateMnemonic generates an English mnemonic from random entropy of length bits
 func GenerateMnemonic(length int) (string, error) {
-	// combine generated entropy and checksum
+	return GenerateMnemonicWithWordlist(length, EnglishWordlist)
+}
+
+// GenerateMnemonicInLanguage generates a mnemonic with the wordlist of the language
+func GenerateMnemonicInLanguage(length int, language Language) (string, error) {
+	wordlist, err := GetWordlist(language)
+	if err != nil {
+		return "", err
+	}
+	return GenerateMnemonicWithWordlist(length, wordlist)
+}
+
+// GenerateMnemonicWithWordlist generates a mnemonic with the wordlist
+func GenerateMnemonicWithWordlist(length int, wordlist *Wordlist) (string, error) {
 	entropy, err := GenerateEntropy(length)
 	if err != nil {
 		return "", err
 	}
+	return NewMnemonicWithWordlist(entropy, wordlist)
+}
+
+// GenerateMnemonicWithWords generates a mnemonic of 12, 15, 18, 21 or 24 words
+func GenerateMnemonicWithWords(wordCount int) (string, error) {
+	if wordCount%3 != 0 {
+		return "", ErrInvalidMnemonicLength
+	}
+	// every 3 words carry 32 bits of entropy and 1 bit of checksum
+	length := wordCount / 3 * 32
+	if ValidateEntropyLength(length) != nil {
+		return "", ErrInvalidMnemonicLength
+	}
+	return GenerateMnemonic(length)
+}
+
+// NewMnemonic converts the entropy to the English mnemonic
+func NewMnemonic(entropy []byte) (string, error) {
+	return NewMnemonicWithWordlist(entropy, EnglishWordlist)
+}
+
+// NewMnemonicWithWordlist converts the entropy to the mnemonic of the wordlist
+func NewMnemonicWithWordlist(entropy []byte, wordlist *Wordlist) (string, error) {
+	err := ValidateEntropyLength(len(entropy) * 8)
+	if err != nil {
+		return "", err
+	}
+
+	// combine entropy and checksum
 	checksum := CalculateChecksum(entropy)
 	sequence := append(ByteToBit(entropy), checksum...)
 
-	// split bits into 12 or 24 segments of 11-bits each
+	// split bits into 12, 15, 18, 21 or 24 segments of 11-bits each
 	// and retrived a BIP-39 mnemonic word
 	chunkSize := 11
 	sequenceCount := len(sequence)/chunkSize
@@ -30,34 +73,61 @@ func GenerateMnemonic(length int) (string, error) {
 	for i:=0; i<int(sequenceCount); i++ {
 		seg := sequence[i*chunkSize:(i+1)*chunkSize]
 		num, _ := strconv.ParseInt(string(seg), 2, 64)
-		words[i], err = getWord(int(num))
+		words[i], err = wordlist.Word(int(num))
 		if err != nil {
 			return "", err
 		}
 	}
-	return strings.Join(words, " "), nil
+	return wordlist.Join(words), nil
+}
+
+// GenerateMasterSeed generates BIP-39 seed from the mnemonic and the optional passphrase
+func GenerateMasterSeed(mnemonic []byte, passphrase []byte) []byte {
+	return NewSeed(string(mnemonic), string(passphrase))
 }
 
-func GenerateMasterSeed(mnemonic []byte, salt []byte) []byte {
-	pbkdf2Key := pbkdf2.Key(mnemonic, []byte("mnemonic1234"), 2048, 64, sha512.New)
+// NewSeed stretches the NFKD normalized mnemonic with PBKDF2-HMAC-SHA512,
+// salted with "mnemonic" followed by the passphrase (the "25th word")
+func NewSeed(mnemonic string, passphrase string) []byte {
+	password := norm.NFKD.String(mnemonic)
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+	pbkdf2Key := pbkdf2.Key([]byte(password), []byte(salt), 2048, 64, sha512.New)
 
 	return pbkdf2Key
 }
 
+// NewSeedWithValidation validates the mnemonic before deriving the seed
+func NewSeedWithValidation(mnemonic string, passphrase string) ([]byte, error) {
+	err := ValidateMnemonic(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	return NewSeed(mnemonic, passphrase), nil
+}
+
 // GenerateEntropy generate a random number in bytes length
 func GenerateEntropy(length int) ([]byte, error) {
-	if length != 128 && length != 256 {
-		return nil, errors.New("Invalid length: must be either 128 or 256")
+	err := ValidateEntropyLength(length)
+	if err != nil {
+		return nil, err
 	}
 
 	bytes := make([]byte, length/8)
-	_, err := rand.Read(bytes)
+	_, err = rand.Read(bytes)
 	if err != nil {
 		return nil, err
 	}
 	return bytes, nil
 }
 
+// ValidateEntropyLength checks that length is one of the BIP-39 entropy sizes
+func ValidateEntropyLength(length int) error {
+	if length < 128 || length > 256 || length%32 != 0 {
+		return errors.New("Invalid length: must be 128, 160, 192, 224 or 256")
+	}
+	return nil
+}
+
 // CalculateChecksum calculate checksum
 func CalculateChecksum(data []byte) bits {
 	h := sha256.New()
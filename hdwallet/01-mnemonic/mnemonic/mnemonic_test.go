@@ -1,9 +1,15 @@
 package mnemonic
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"testing"
 	"log"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 const length = 256
@@ -17,4 +23,507 @@ func TestMnemonic(t *testing.T) {
 
 	masterSeed := GenerateMasterSeed([]byte(mnemonic), []byte("mnemonic1234"))
 	log.Println("masterSeed: 0x" + hex.EncodeToString(masterSeed))
-}
\ No newline at end of file
+}
+
+func TestParseMnemonic(t *testing.T) {
+	for _, length := range []int{128, 160, 192, 224, 256} {
+		mnemonic, err := GenerateMnemonic(length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entropy, err := ParseMnemonic(mnemonic)
+		if err != nil {
+			t.Fatalf("parse %q: %v", mnemonic, err)
+		}
+		if len(entropy) != length/8 {
+			t.Fatalf("entropy length: got %d, want %d", len(entropy), length/8)
+		}
+	}
+
+	entropy, err := ParseMnemonic("legal winner thank year wave sausage worth useful legal winner thank yellow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(entropy) != "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f" {
+		t.Fatalf("entropy: got %x", entropy)
+	}
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	err := ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon")
+	if !errors.Is(err, ErrInvalidChecksum) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidChecksum)
+	}
+
+	err = ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+	if !errors.Is(err, ErrInvalidMnemonicLength) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidMnemonicLength)
+	}
+
+	err = ValidateMnemonic("abandon abandon abandon abandon abandon abandn abandon abandon abandon abandon abandon about")
+	var unknownWord *UnknownWordError
+	if !errors.As(err, &unknownWord) {
+		t.Fatalf("got %v, want UnknownWordError", err)
+	}
+	if unknownWord.Word != "abandn" || unknownWord.Position != 5 {
+		t.Fatalf("got word %q at %d", unknownWord.Word, unknownWord.Position)
+	}
+}
+
+
+var bip39Vectors = []struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}{
+	{
+		"00000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		"80808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		"d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+	},
+	{
+		"ffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+	{
+		"000000000000000000000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon agent",
+		"035895f2f481b1b0f01fcf8c289c794660b289981a78f8106447707fdd9666ca06da5a9a565181599b79f53b844d8a71dd9f439c52a3d7b3e8a79c906ac845fa",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful legal will",
+		"f2b94508732bcbacbcc020faefecfc89feafa6649a5491b8c952cede496c214a0c7b3c392d168748f2d4a612bada0753b52a1c7ac53c1e93abd5c6320b9e95dd",
+	},
+	{
+		"808080808080808080808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+		"107d7c02a5aa6f38c58083ff74f04c607c2d2c0ecc55501dadd72d025b751bc27fe913ffb796f841c49b1d33b610cf0e91d3aa239027f5e99fe4ce9e5088cd65",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo when",
+		"0cd6e5d827bb62eb8fc1e262254223817fd068a74b5b449cc2f667c3f1f985a76379b43348d952e2265b4cd129090758b3e3c2c49103b5051aac2eaeb890a528",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth title",
+		"bc09fca1804f7e69da93c2f2028eb238c227f2e9dda30cd63699232578480a4021b146ad717fbb7e451ce9eb835f43620bf5c514db0f8add49f5d121449d3e87",
+	},
+	{
+		"8080808080808080808080808080808080808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic bless",
+		"c0c519bd0e91a2ed54357d9d1ebef6f5af218a153624cf4f2da911a0ed8f7a09e2ef61af0aca007096df430022f7a2b6fb91661a9589097069720d015e4e982f",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+		"dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+	},
+	{
+		"77c2b00716cec7213839159e404db50d",
+		"jelly better achieve collect unaware mountain thought cargo oxygen act hood bridge",
+		"b5b6d0127db1a9d2226af0c3346031d77af31e918dba64287a1b44b8ebf63cdd52676f672a290aae502472cf2d602c051f3e6f18055e84e4c43897fc4e51a6ff",
+	},
+	{
+		"b63a9c59a6e641f288ebc103017f1da9f8290b3da6bdef7b",
+		"renew stay biology evidence goat welcome casual join adapt armor shuffle fault little machine walk stumble urge swap",
+		"9248d83e06f4cd98debf5b6f010542760df925ce46cf38a1bdb4e4de7d21f5c39366941c69e1bdbf2966e0f6e6dbece898a0e2f0a4c2b3e640953dfe8b7bbdc5",
+	},
+	{
+		"3e141609b97933b66a060dcddc71fad1d91677db872031e85f4c015c5e7e8982",
+		"dignity pass list indicate nasty swamp pool script soccer toe leaf photo multiply desk host tomato cradle drill spread actor shine dismiss champion exotic",
+		"ff7f3184df8696d8bef94b6c03114dbee0ef89ff938712301d27ed8336ca89ef9635da20af07d4175f2bf5f3de130f39c9d9e8dd0472489c19b1a020a940da67",
+	},
+	{
+		"0460ef47585604c5660618db2e6a7e7f",
+		"afford alter spike radar gate glance object seek swamp infant panel yellow",
+		"65f93a9f36b6c85cbe634ffc1f99f2b82cbb10b31edc7f087b4f6cb9e976e9faf76ff41f8f27c99afdf38f7a303ba1136ee48a4c1e7fcd3dba7aa876113a36e4",
+	},
+	{
+		"72f60ebac5dd8add8d2a25a797102c3ce21bc029c200076f",
+		"indicate race push merry suffer human cruise dwarf pole review arch keep canvas theme poem divorce alter left",
+		"3bbf9daa0dfad8229786ace5ddb4e00fa98a044ae4c4975ffd5e094dba9e0bb289349dbe2091761f30f382d4e35c4a670ee8ab50758d2c55881be69e327117ba",
+	},
+	{
+		"2c85efc7f24ee4573d2b81a6ec66cee209b2dcbd09d8eddc51e0215b0b68e416",
+		"clutch control vehicle tonight unusual clog visa ice plunge glimpse recipe series open hour vintage deposit universe tip job dress radar refuse motion taste",
+		"fe908f96f46668b2d5b37d82f558c77ed0d69dd0e7e043a5b0511c48c2f1064694a956f86360c93dd04052a8899497ce9e985ebe0c8c52b955e6ae86d4ff4449",
+	},
+	{
+		"eaebabb2383351fd31d703840b32e9e2",
+		"turtle front uncle idea crush write shrug there lottery flower risk shell",
+		"bdfb76a0759f301b0b899a1e3985227e53b3f51e67e3f2a65363caedf3e32fde42a66c404f18d7b05818c95ef3ca1e5146646856c461c073169467511680876c",
+	},
+	{
+		"7ac45cfe7722ee6c7ba84fbc2d5bd61b45cb2fe5eb65aa78",
+		"kiss carry display unusual confirm curtain upgrade antique rotate hello void custom frequent obey nut hole price segment",
+		"ed56ff6c833c07982eb7119a8f48fd363c4a9b1601cd2de736b01045c5eb8ab4f57b079403485d1c4924f0790dc10a971763337cb9f9c62226f64fff26397c79",
+	},
+	{
+		"4fa1a8bc3e6d80ee1316050e862c1812031493212b7ec3f3bb1b08f168cabeef",
+		"exile ask congress lamp submit jacket era scheme attend cousin alcohol catch course end lucky hurt sentence oven short ball bird grab wing top",
+		"095ee6f817b4c2cb30a5a797360a81a40ab0f9a4e25ecd672a3f58a0b5ba0687c096a6b14d2c0deb3bdefce4f61d01ae07417d502429352e27695163f7447a8c",
+	},
+	{
+		"18ab19a9f54a9274f03e5209a2ac8a91",
+		"board flee heavy tunnel powder denial science ski answer betray cargo cat",
+		"6eff1bb21562918509c73cb990260db07c0ce34ff0e3cc4a8cb3276129fbcb300bddfe005831350efd633909f476c45c88253276d9fd0df6ef48609e8bb7dca8",
+	},
+	{
+		"18a2e1d81b8ecfb2a333adcb0c17a5b9eb76cc5d05db91a4",
+		"board blade invite damage undo sun mimic interest slam gaze truly inherit resist great inject rocket museum chief",
+		"f84521c777a13b61564234bf8f8b62b3afce27fc4062b51bb5e62bdfecb23864ee6ecf07c1d5a97c0834307c5c852d8ceb88e7c97923c0a3b496bedd4e5f88a9",
+	},
+	{
+		"15da872c95a13dd738fbf50e427583ad61f18fd99f628c417a61cf8343c90419",
+		"beyond stage sleep clip because twist token leaf atom beauty genius food business side grid unable middle armed observe pair crouch tonight away coconut",
+		"b15509eaa2d09d3efd3e006ef42151b30367dc6e3aa5e44caba3fe4d3e352e65101fbdb86a96776b91946ff06f8eac594dc6ee1d3e82a42dfe1b40fef6bcc3fd",
+	},
+}
+
+func TestNewSeed(t *testing.T) {
+	for _, vector := range bip39Vectors {
+		seed := NewSeed(vector.mnemonic, "TREZOR")
+		if hex.EncodeToString(seed) != vector.seed {
+			t.Fatalf("seed of %q: got %x, want %s", vector.mnemonic, seed, vector.seed)
+		}
+	}
+
+	// passphrase is NFKD normalized, so composed and decomposed forms derive the same seed
+	mnemonic := bip39Vectors[0].mnemonic
+	composed := NewSeed(mnemonic, "caf\u00e9")
+	decomposed := NewSeed(mnemonic, "cafe\u0301")
+	if !bytes.Equal(composed, decomposed) {
+		t.Fatalf("seed differs by passphrase normalization: %x != %x", composed, decomposed)
+	}
+
+	_, err := NewSeedWithValidation("zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo", "TREZOR")
+	if !errors.Is(err, ErrInvalidChecksum) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidChecksum)
+	}
+}
+
+func TestMnemonicVectors(t *testing.T) {
+	for _, vector := range bip39Vectors {
+		entropy, _ := hex.DecodeString(vector.entropy)
+		mnemonic, err := NewMnemonic(entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mnemonic != vector.mnemonic {
+			t.Fatalf("mnemonic of %s: got %q, want %q", vector.entropy, mnemonic, vector.mnemonic)
+		}
+
+		parsed, err := ParseMnemonic(vector.mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(parsed, entropy) {
+			t.Fatalf("entropy of %q: got %x, want %s", vector.mnemonic, parsed, vector.entropy)
+		}
+	}
+}
+
+func TestGenerateMnemonicWithWords(t *testing.T) {
+	for _, wordCount := range []int{12, 15, 18, 21, 24} {
+		mnemonic, err := GenerateMnemonicWithWords(wordCount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(strings.Fields(mnemonic)) != wordCount {
+			t.Fatalf("got %d words, want %d", len(strings.Fields(mnemonic)), wordCount)
+		}
+		err = ValidateMnemonic(mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, wordCount := range []int{0, 9, 13, 27} {
+		_, err := GenerateMnemonicWithWords(wordCount)
+		if !errors.Is(err, ErrInvalidMnemonicLength) {
+			t.Fatalf("%d words: got %v, want %v", wordCount, err, ErrInvalidMnemonicLength)
+		}
+	}
+
+	_, err := GenerateEntropy(136)
+	if err == nil {
+		t.Fatal("136 bits entropy should be rejected")
+	}
+}
+
+func TestWordlists(t *testing.T) {
+	entropy, _ := hex.DecodeString("7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f")
+	for _, language := range Languages() {
+		wordlist, err := GetWordlist(language)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(wordlist.Words()) != 2048 {
+			t.Fatalf("%s: got %d words", language, len(wordlist.Words()))
+		}
+
+		mnemonic, err := NewMnemonicWithWordlist(entropy, wordlist)
+		if err != nil {
+			t.Fatal(err)
+		}
+		detected, err := DetectLanguage(mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if detected.Language() != language {
+			t.Fatalf("detected %s, want %s", detected.Language(), language)
+		}
+		parsed, err := ParseMnemonic(mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(parsed, entropy) {
+			t.Fatalf("%s: got entropy %x", language, parsed)
+		}
+	}
+
+	_, err := DetectLanguage("foo bar baz")
+	if !errors.Is(err, ErrUnknownLanguage) {
+		t.Fatalf("got %v, want %v", err, ErrUnknownLanguage)
+	}
+
+	_, err = GetWordlist(Portuguese)
+	if !errors.Is(err, ErrWordlistNotAvailable) {
+		t.Fatalf("got %v, want %v", err, ErrWordlistNotAvailable)
+	}
+}
+
+// wordlistHashes are the sha256 sums of the official bitcoin/bips wordlist files
+var wordlistHashes = map[Language]string{
+	English:            "2f5eed53a4727b4bf8880d8f3f199efc90e58503646d9ff8eff3a2ed3b24dbda",
+	Japanese:           "2eed0aef492291e061633d7ad8117f1a2b03eb80a29d0e4e3117ac2528d05ffd",
+	Korean:             "9e95f86c167de88f450f0aaf89e87f6624a57f973c67b516e338e8e8b8897f60",
+	Spanish:            "46846a5a0139d1e3cb77293e521c2865f7bcdb82c44e8d0a06a2cd0ecba48c0b",
+	ChineseSimplified:  "5c5942792bd8340cb8b27cd592f1015edf56a8c5b26276ee18a482428e7c5726",
+	ChineseTraditional: "417b26b3d8500a4ae3d59717d7011952db6fc2fb84b807f3f94ac734e89c1b5f",
+	French:             "ebc3959ab7801a1df6bac4fa7d970652f1df76b683cd2f4003c941c63d517e59",
+	Italian:            "d392c49fdb700a24cd1fceb237c1f65dcc128f6b34a8aacb58b59384b5c648c2",
+	Czech:              "7e80e161c3e93d9554c2efb78d4e3cebf8fc727e9c52e03b83b94406bdcc95fc",
+}
+
+func TestWordlistHashes(t *testing.T) {
+	for _, language := range Languages() {
+		wordlist, err := GetWordlist(language)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the files hold one word per line with a trailing newline
+		sum := sha256.Sum256([]byte(strings.Join(wordlist.Words(), "\n") + "\n"))
+		if hex.EncodeToString(sum[:]) != wordlistHashes[language] {
+			t.Fatalf("%s: got sha256 %x, want %s", language, sum, wordlistHashes[language])
+		}
+	}
+}
+
+func TestJapaneseMnemonic(t *testing.T) {
+	entropy := make([]byte, 16)
+	mnemonic, err := NewMnemonicWithWordlist(entropy, JapaneseWordlist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あおぞら"
+	if norm.NFC.String(mnemonic) != want {
+		t.Fatalf("got %q, want %q", mnemonic, want)
+	}
+
+	seed := NewSeed(mnemonic, "㍍ガバヴァぱばぐゞちぢ十人十色")
+	if hex.EncodeToString(seed) != "a262d6fb6122ecf45be09c50492b31f92e9beb7d9a845987a02cefda57a15f9c467a17872029a9e92299b5cbdf306e3a0ee620245cbd508959b6cb7ca637bd55" {
+		t.Fatalf("got seed %x", seed)
+	}
+}
+
+func TestSpanishNormalization(t *testing.T) {
+	// composed "á" is accepted for the decomposed word in the wordlist
+	index, ok := SpanishWordlist.Index("ábaco")
+	if !ok || index != 0 {
+		t.Fatalf("got %d, %v", index, ok)
+	}
+}
+
+func TestEntropyFromDice(t *testing.T) {
+	rolls := "12345612345612345612345612345612345612345612345612"
+	entropy, err := EntropyFromDice(rolls, 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(entropy) != "91d89c79009852800bffccdc406b1ba0" {
+		t.Fatalf("got %x", entropy)
+	}
+
+	_, err = EntropyFromDice(rolls[:49], 128)
+	if !errors.Is(err, ErrInsufficientEntropy) {
+		t.Fatalf("got %v, want %v", err, ErrInsufficientEntropy)
+	}
+
+	_, err = EntropyFromDice(strings.Repeat("7", 50), 128)
+	if err == nil {
+		t.Fatal("dice roll 7 should be rejected")
+	}
+
+	_, err = EntropyFromDice(rolls+"3", 128)
+	if err == nil || errors.Is(err, ErrInsufficientEntropy) {
+		t.Fatalf("surplus dice roll: got %v, want count error", err)
+	}
+
+	// rolls are counted as characters, not bytes
+	_, err = EntropyFromDice(rolls[:48]+"é", 128)
+	if !errors.Is(err, ErrInsufficientEntropy) {
+		t.Fatalf("got %v, want %v", err, ErrInsufficientEntropy)
+	}
+}
+
+func TestEntropyFromCoinFlips(t *testing.T) {
+	mnemonic, err := NewMnemonicFromCoinFlips(strings.Repeat("0111 1111 ", 16), 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mnemonic != bip39Vectors[1].mnemonic {
+		t.Fatalf("got %q, want %q", mnemonic, bip39Vectors[1].mnemonic)
+	}
+
+	entropy, err := EntropyFromCoinFlips(strings.Repeat("HT", 64), 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(entropy) != "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa" {
+		t.Fatalf("got %x", entropy)
+	}
+
+	_, err = EntropyFromCoinFlips(strings.Repeat("1", 127), 128)
+	if !errors.Is(err, ErrInsufficientEntropy) {
+		t.Fatalf("got %v, want %v", err, ErrInsufficientEntropy)
+	}
+
+	_, err = EntropyFromCoinFlips(strings.Repeat("1", 129), 128)
+	if err == nil || errors.Is(err, ErrInsufficientEntropy) {
+		t.Fatalf("surplus coin flip: got %v, want count error", err)
+	}
+
+	// flips are counted as characters, not bytes
+	_, err = EntropyFromCoinFlips(strings.Repeat("1", 126)+"é", 128)
+	if !errors.Is(err, ErrInsufficientEntropy) {
+		t.Fatalf("got %v, want %v", err, ErrInsufficientEntropy)
+	}
+}
+
+func TestEntropyFromHex(t *testing.T) {
+	for _, vector := range bip39Vectors {
+		mnemonic, err := NewMnemonicFromHex(vector.entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mnemonic != vector.mnemonic {
+			t.Fatalf("got %q, want %q", mnemonic, vector.mnemonic)
+		}
+	}
+
+	_, err := EntropyFromHex("0x7f7f7f7f7f7f7f7f")
+	if !errors.Is(err, ErrInsufficientEntropy) {
+		t.Fatalf("got %v, want %v", err, ErrInsufficientEntropy)
+	}
+
+	for _, prefix := range []string{"0x", "0X"} {
+		entropy, err := EntropyFromHex(prefix + bip39Vectors[0].entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(entropy) != bip39Vectors[0].entropy {
+			t.Fatalf("got %x, want %s", entropy, bip39Vectors[0].entropy)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	candidates := EnglishWordlist.Complete("aba")
+	if strings.Join(candidates, " ") != "abandon" {
+		t.Fatalf("got %v", candidates)
+	}
+
+	candidates = EnglishWordlist.Complete("ab")
+	if len(candidates) != 10 {
+		t.Fatalf("got %v", candidates)
+	}
+
+	// the first 4 letters identify the word even when the rest is mistyped
+	candidates = EnglishWordlist.Complete("abanbon")
+	if strings.Join(candidates, " ") != "abandon" {
+		t.Fatalf("got %v", candidates)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	suggestions := EnglishWordlist.Suggest("abandn")
+	if len(suggestions) == 0 || suggestions[0] != "abandon" {
+		t.Fatalf("got %v", suggestions)
+	}
+
+	suggestions = EnglishWordlist.Suggest("zoo")
+	if strings.Join(suggestions, " ") != "zoo" {
+		t.Fatalf("got %v", suggestions)
+	}
+}
+
+func TestRepairMnemonic(t *testing.T) {
+	vector := bip39Vectors[12]
+	words := strings.Fields(vector.mnemonic)
+	words[4] = "unawre"
+
+	candidates, err := RepairMnemonic(strings.Join(words, " "), -1, EnglishWordlist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, candidate := range candidates {
+		if candidate == vector.mnemonic {
+			found = true
+		}
+		err = ValidateMnemonic(candidate)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !found {
+		t.Fatalf("%q is not among %d candidates", vector.mnemonic, len(candidates))
+	}
+
+	// a phrase of known words needs the position of the mistyped word
+	_, err = RepairMnemonic(vector.mnemonic, -1, EnglishWordlist)
+	if !errors.Is(err, ErrNothingToRepair) {
+		t.Fatalf("got %v, want %v", err, ErrNothingToRepair)
+	}
+
+	words[5] = "mountian"
+	_, err = RepairMnemonic(strings.Join(words, " "), -1, EnglishWordlist)
+	var unknownWord *UnknownWordError
+	if !errors.As(err, &unknownWord) || unknownWord.Position != 5 {
+		t.Fatalf("got %v, want UnknownWordError at position 5", err)
+	}
+}
@@ -0,0 +1,93 @@
+package mnemonic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidMnemonicLength = errors.New("Invalid mnemonic length: must be 12, 15, 18, 21 or 24 words")
+	ErrInvalidChecksum       = errors.New("Invalid mnemonic checksum: phrase contains a mistyped or misplaced word")
+)
+
+// UnknownWordError reports a word which does not exist in the wordlist.
+// Position is the zero-based index of the word in the phrase.
+type UnknownWordError struct {
+	Word     string
+	Position int
+}
+
+func (e *UnknownWordError) Error() string {
+	return fmt.Sprintf("Unknown mnemonic word %q at position %d", e.Word, e.Position+1)
+}
+
+// ParseMnemonic validates the mnemonic phrase and returns the original entropy.
+// The language of the mnemonic is detected from its words.
+func ParseMnemonic(mnemonic string) ([]byte, error) {
+	candidates, closest := detectWordlists(splitMnemonic(mnemonic))
+	if len(candidates) == 0 {
+		// report the unknown word against the closest wordlist
+		return ParseMnemonicWithWordlist(mnemonic, closest)
+	}
+
+	var err error
+	for _, wordlist := range candidates {
+		var entropy []byte
+		entropy, err = ParseMnemonicWithWordlist(mnemonic, wordlist)
+		if err == nil {
+			return entropy, nil
+		}
+	}
+	return nil, err
+}
+
+// ParseMnemonicWithWordlist validates the mnemonic phrase against the wordlist
+// and returns the original entropy
+func ParseMnemonicWithWordlist(mnemonic string, wordlist *Wordlist) ([]byte, error) {
+	words := splitMnemonic(mnemonic)
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, ErrInvalidMnemonicLength
+	}
+
+	// join 11-bits index of each word into a single sequence
+	sequence := make(bits, 0, len(words)*11)
+	for i, word := range words {
+		index, ok := wordlist.Index(word)
+		if !ok {
+			return nil, &UnknownWordError{Word: word, Position: i}
+		}
+		seg := strconv.FormatInt(int64(index), 2)
+		sequence = append(sequence, strings.Repeat("0", 11-len(seg))+seg...)
+	}
+
+	// sequence consists of ENT bits of entropy followed by ENT/32 bits of checksum
+	entropyLength := len(sequence) * 32 / 33
+	entropy := BitToByte(sequence[:entropyLength])
+	if !bytes.Equal(CalculateChecksum(entropy), sequence[entropyLength:]) {
+		return nil, ErrInvalidChecksum
+	}
+
+	return entropy, nil
+}
+
+// ValidateMnemonic checks word count, words and checksum of the mnemonic phrase
+// in any supported language
+func ValidateMnemonic(mnemonic string) error {
+	_, err := ParseMnemonic(mnemonic)
+	return err
+}
+
+// BitToByte convert bits type data to byte data
+func BitToByte(b bits) []byte {
+	data := make([]byte, (len(b)+7)/8)
+
+	for i, bit := range b {
+		if bit == '1' {
+			data[i/8] |= 1 << uint8(7-i%8)
+		}
+	}
+	return data
+}
@@ -0,0 +1,159 @@
+package mnemonic
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// uniquePrefixLength is the number of letters identifying a word in the
+// English, Spanish, French, Italian and Czech wordlists
+const uniquePrefixLength = 4
+
+// maxEditDistance limits how far a misspelled word may be from a suggestion
+const maxEditDistance = 2
+
+// ErrNothingToRepair is returned when no position is given and every word belongs to the wordlist
+var ErrNothingToRepair = errors.New("Nothing to repair: every word is in the wordlist, pass the position of the mistyped word")
+
+// Complete returns the words of the wordlist starting with the prefix.
+// Once the prefix reaches 4 letters, a word whose first 4 letters match
+// is returned even if the rest of the prefix is mistyped.
+func (w *Wordlist) Complete(prefix string) []string {
+	prefix = norm.NFKD.String(strings.ToLower(strings.TrimSpace(prefix)))
+	if prefix == "" {
+		return nil
+	}
+
+	var candidates []string
+	for _, word := range w.words {
+		if strings.HasPrefix(norm.NFKD.String(word), prefix) {
+			candidates = append(candidates, word)
+		}
+	}
+	if len(candidates) > 0 || len([]rune(prefix)) < uniquePrefixLength {
+		return candidates
+	}
+
+	short := string([]rune(prefix)[:uniquePrefixLength])
+	for _, word := range w.words {
+		if strings.HasPrefix(norm.NFKD.String(word), short) {
+			candidates = append(candidates, word)
+		}
+	}
+	return candidates
+}
+
+// Suggest returns the words of the wordlist closest to a misspelled word,
+// ordered by edit distance. A known word is returned as is.
+func (w *Wordlist) Suggest(word string) []string {
+	word = norm.NFKD.String(strings.ToLower(strings.TrimSpace(word)))
+	if word == "" {
+		return nil
+	}
+	if index, ok := w.Index(word); ok {
+		return []string{w.words[index]}
+	}
+
+	type suggestion struct {
+		word     string
+		distance int
+	}
+	var suggestions []suggestion
+	for _, candidate := range w.words {
+		distance := editDistance(word, norm.NFKD.String(candidate))
+		if distance <= maxEditDistance {
+			suggestions = append(suggestions, suggestion{candidate, distance})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].distance < suggestions[j].distance
+	})
+
+	words := make([]string, 0, len(suggestions))
+	seen := make(map[string]bool)
+	for _, s := range suggestions {
+		words = append(words, s.word)
+		seen[s.word] = true
+	}
+
+	// a truncated word is completed as well
+	for _, candidate := range w.Complete(word) {
+		if !seen[candidate] {
+			words = append(words, candidate)
+		}
+	}
+	return words
+}
+
+// RepairMnemonic returns every mnemonic obtained by replacing the word at
+// position (zero-based) with a word of the wordlist that yields a valid
+// checksum. Pass position -1 to replace the only unknown word of the phrase;
+// ErrNothingToRepair is returned if there is none.
+func RepairMnemonic(mnemonic string, position int, wordlist *Wordlist) ([]string, error) {
+	words := splitMnemonic(mnemonic)
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, ErrInvalidMnemonicLength
+	}
+
+	if position < 0 {
+		for i, word := range words {
+			if _, ok := wordlist.Index(word); ok {
+				continue
+			}
+			if position >= 0 {
+				return nil, &UnknownWordError{Word: word, Position: i}
+			}
+			position = i
+		}
+		if position < 0 {
+			return nil, ErrNothingToRepair
+		}
+	}
+	if position >= len(words) {
+		return nil, ErrInvalidMnemonicLength
+	}
+
+	// every other word must belong to the wordlist
+	for i, word := range words {
+		if _, ok := wordlist.Index(word); !ok && i != position {
+			return nil, &UnknownWordError{Word: word, Position: i}
+		}
+	}
+
+	var candidates []string
+	for _, replacement := range wordlist.words {
+		words[position] = replacement
+		candidate := wordlist.Join(words)
+		_, err := ParseMnemonicWithWordlist(candidate, wordlist)
+		if err == nil {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates, nil
+}
+
+// editDistance returns the Levenshtein distance between two words
+func editDistance(a string, b string) int {
+	s, t := []rune(a), []rune(b)
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		current[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(t)]
+}
@@ -0,0 +1,180 @@
+package mnemonic
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type Language string
+
+const (
+	English            Language = "english"
+	Japanese           Language = "japanese"
+	Korean             Language = "korean"
+	Spanish            Language = "spanish"
+	ChineseSimplified  Language = "chinese_simplified"
+	ChineseTraditional Language = "chinese_traditional"
+	French             Language = "french"
+	Italian            Language = "italian"
+	Czech              Language = "czech"
+	// Portuguese is an official BIP-39 language whose wordlist is not bundled yet
+	Portuguese Language = "portuguese"
+)
+
+var (
+	ErrUnknownLanguage      = errors.New("Unknown mnemonic language: words do not belong to any supported wordlist")
+	ErrWordlistNotAvailable = errors.New("Unsupported language: portuguese wordlist is not bundled yet")
+)
+
+// Wordlist is a BIP-39 dictionary of 2048 words
+type Wordlist struct {
+	language  Language
+	words     []string
+	index     map[string]int
+	separator string
+}
+
+func newWordlist(language Language, words []string, separator string) *Wordlist {
+	// words are looked up by NFKD normalized form, so composed and
+	// decomposed spellings of accented words resolve to the same index
+	index := make(map[string]int, len(words))
+	for i, word := range words {
+		index[norm.NFKD.String(word)] = i
+	}
+
+	return &Wordlist{
+		language:  language,
+		words:     words,
+		index:     index,
+		separator: separator,
+	}
+}
+
+var (
+	EnglishWordlist            = newWordlist(English, bip44WordList, " ")
+	JapaneseWordlist           = newWordlist(Japanese, japaneseWordList, "　")
+	KoreanWordlist             = newWordlist(Korean, koreanWordList, " ")
+	SpanishWordlist            = newWordlist(Spanish, spanishWordList, " ")
+	ChineseSimplifiedWordlist  = newWordlist(ChineseSimplified, chineseSimplifiedWordList, " ")
+	ChineseTraditionalWordlist = newWordlist(ChineseTraditional, chineseTraditionalWordList, " ")
+	FrenchWordlist             = newWordlist(French, frenchWordList, " ")
+	ItalianWordlist            = newWordlist(Italian, italianWordList, " ")
+	CzechWordlist              = newWordlist(Czech, czechWordList, " ")
+)
+
+// wordlists is ordered by detection priority
+var wordlists = []*Wordlist{
+	EnglishWordlist,
+	JapaneseWordlist,
+	KoreanWordlist,
+	SpanishWordlist,
+	ChineseSimplifiedWordlist,
+	ChineseTraditionalWordlist,
+	FrenchWordlist,
+	ItalianWordlist,
+	CzechWordlist,
+}
+
+// GetWordlist returns the wordlist of the language
+func GetWordlist(language Language) (*Wordlist, error) {
+	for _, w := range wordlists {
+		if w.language == language {
+			return w, nil
+		}
+	}
+	if language == Portuguese {
+		return nil, ErrWordlistNotAvailable
+	}
+	return nil, errors.New("Unsupported language: " + string(language))
+}
+
+// Languages returns every supported language
+func Languages() []Language {
+	languages := make([]Language, len(wordlists))
+	for i, w := range wordlists {
+		languages[i] = w.language
+	}
+	return languages
+}
+
+func (w *Wordlist) Language() Language {
+	return w.language
+}
+
+// Separator returns the string joining the words of a mnemonic,
+// which is an ideographic space for Japanese and a space otherwise
+func (w *Wordlist) Separator() string {
+	return w.separator
+}
+
+// Words returns a copy of the words in the wordlist
+func (w *Wordlist) Words() []string {
+	return append([]string(nil), w.words...)
+}
+
+// Word returns the word at the index
+func (w *Wordlist) Word(index int) (string, error) {
+	if index >= 0 && index < len(w.words) {
+		return w.words[index], nil
+	}
+	return "", errors.New("Index out of bounds: valid indices are 0 to 2047, but  recieved " + strconv.Itoa(index))
+}
+
+// Index returns the index of the word
+func (w *Wordlist) Index(word string) (int, bool) {
+	index, ok := w.index[norm.NFKD.String(word)]
+	return index, ok
+}
+
+// Join joins the words with the separator of the wordlist
+func (w *Wordlist) Join(words []string) string {
+	return strings.Join(words, w.separator)
+}
+
+// DetectLanguage returns the wordlist which contains every word of the mnemonic.
+// When the words belong to several wordlists, the one with a valid checksum wins.
+func DetectLanguage(mnemonic string) (*Wordlist, error) {
+	candidates, _ := detectWordlists(splitMnemonic(mnemonic))
+	if len(candidates) == 0 {
+		return nil, ErrUnknownLanguage
+	}
+
+	for _, w := range candidates {
+		_, err := ParseMnemonicWithWordlist(mnemonic, w)
+		if err == nil {
+			return w, nil
+		}
+	}
+	return candidates[0], nil
+}
+
+// detectWordlists returns the wordlists containing every word and
+// the wordlist containing the most words
+func detectWordlists(words []string) ([]*Wordlist, *Wordlist) {
+	var candidates []*Wordlist
+	closest, closestCount := wordlists[0], -1
+	for _, w := range wordlists {
+		count := 0
+		for _, word := range words {
+			if _, ok := w.Index(word); ok {
+				count++
+			}
+		}
+
+		if count == len(words) {
+			candidates = append(candidates, w)
+		}
+		if count > closestCount {
+			closest, closestCount = w, count
+		}
+	}
+	return candidates, closest
+}
+
+// splitMnemonic splits the mnemonic into NFKD normalized lower case words
+func splitMnemonic(mnemonic string) []string {
+	return strings.Fields(norm.NFKD.String(strings.ToLower(mnemonic)))
+}
@@ -0,0 +1,57 @@
+package slip39
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	baseIterationCount = 10000
+	roundCount         = 4
+)
+
+// salt is "shamir" followed by the identifier, or empty for extendable backups
+func salt(identifier uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+	return append([]byte(customizationString), byte(identifier>>8), byte(identifier))
+}
+
+func roundFunction(i int, passphrase []byte, iterationExponent int, salt []byte, r []byte) []byte {
+	password := append([]byte{byte(i)}, passphrase...)
+	iterations := (baseIterationCount << iterationExponent) / roundCount
+	return pbkdf2.Key(password, append(append([]byte(nil), salt...), r...), iterations, len(r), sha256.New)
+}
+
+func xor(a []byte, b []byte) []byte {
+	result := make([]byte, len(a))
+	for i := range a {
+		result[i] = a[i] ^ b[i]
+	}
+	return result
+}
+
+// encrypt protects the master secret with the passphrase through a
+// 4-round Feistel network of PBKDF2-HMAC-SHA256
+func encrypt(masterSecret []byte, passphrase []byte, iterationExponent int, identifier uint16, extendable bool) []byte {
+	l := masterSecret[:len(masterSecret)/2]
+	r := masterSecret[len(masterSecret)/2:]
+	s := salt(identifier, extendable)
+	for i := 0; i < roundCount; i++ {
+		l, r = r, xor(l, roundFunction(i, passphrase, iterationExponent, s, r))
+	}
+	return append(append([]byte(nil), r...), l...)
+}
+
+// decrypt recovers the master secret from the encrypted master secret
+func decrypt(encryptedMasterSecret []byte, passphrase []byte, iterationExponent int, identifier uint16, extendable bool) []byte {
+	l := encryptedMasterSecret[:len(encryptedMasterSecret)/2]
+	r := encryptedMasterSecret[len(encryptedMasterSecret)/2:]
+	s := salt(identifier, extendable)
+	for i := roundCount - 1; i >= 0; i-- {
+		l, r = r, xor(l, roundFunction(i, passphrase, iterationExponent, s, r))
+	}
+	return append(append([]byte(nil), r...), l...)
+}
@@ -0,0 +1,139 @@
+package slip39
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+const (
+	digestLength = 4
+	digestIndex  = 254
+	secretIndex  = 255
+)
+
+var ErrInvalidDigest = errors.New("Invalid digest of the shared secret: shares do not belong together")
+
+// exp and log tables of GF(256) with the Rijndael polynomial x^8+x^4+x^3+x+1
+var expTable, logTable = func() ([255]byte, [256]byte) {
+	var exp [255]byte
+	var log [256]byte
+
+	poly := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(poly)
+		log[poly] = byte(i)
+
+		// multiply poly by the generator x+1
+		poly = (poly << 1) ^ poly
+		if poly&0x100 != 0 {
+			poly ^= 0x11B
+		}
+	}
+	return exp, log
+}()
+
+type rawShare struct {
+	x     byte
+	value []byte
+}
+
+// interpolate evaluates at x the polynomial passing through the shares
+// by Lagrange interpolation over GF(256)
+func interpolate(shares []rawShare, x byte) []byte {
+	for _, share := range shares {
+		if share.x == x {
+			return share.value
+		}
+	}
+
+	logProd := 0
+	for _, share := range shares {
+		logProd += int(logTable[share.x^x])
+	}
+
+	result := make([]byte, len(shares[0].value))
+	for _, share := range shares {
+		logBasis := logProd - int(logTable[share.x^x])
+		for _, other := range shares {
+			logBasis -= int(logTable[share.x^other.x])
+		}
+		logBasis = ((logBasis % 255) + 255) % 255
+
+		for i, v := range share.value {
+			if v != 0 {
+				result[i] ^= expTable[(int(logTable[v])+logBasis)%255]
+			}
+		}
+	}
+	return result
+}
+
+func createDigest(randomData []byte, sharedSecret []byte) []byte {
+	h := hmac.New(sha256.New, randomData)
+	h.Write(sharedSecret)
+	return h.Sum(nil)[:digestLength]
+}
+
+// splitSecret splits the secret into shareCount shares, any threshold of
+// which recover the secret. The digest of the secret is stored at x=254
+// so that recovery can detect shares which do not belong together.
+func splitSecret(threshold int, shareCount int, sharedSecret []byte) ([]rawShare, error) {
+	if threshold < 1 || threshold > shareCount || shareCount > maxShareCount {
+		return nil, errors.New("Invalid threshold: must be between 1 and the share count, which is at most 16")
+	}
+
+	shares := make([]rawShare, 0, shareCount)
+	if threshold == 1 {
+		for i := 0; i < shareCount; i++ {
+			shares = append(shares, rawShare{byte(i), append([]byte(nil), sharedSecret...)})
+		}
+		return shares, nil
+	}
+
+	randomShareCount := threshold - 2
+	for i := 0; i < randomShareCount; i++ {
+		value := make([]byte, len(sharedSecret))
+		_, err := rand.Read(value)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, rawShare{byte(i), value})
+	}
+
+	randomPart := make([]byte, len(sharedSecret)-digestLength)
+	_, err := rand.Read(randomPart)
+	if err != nil {
+		return nil, err
+	}
+	digest := createDigest(randomPart, sharedSecret)
+
+	baseShares := append([]rawShare(nil), shares...)
+	baseShares = append(baseShares,
+		rawShare{digestIndex, append(digest, randomPart...)},
+		rawShare{secretIndex, sharedSecret},
+	)
+	for i := randomShareCount; i < shareCount; i++ {
+		shares = append(shares, rawShare{byte(i), interpolate(baseShares, byte(i))})
+	}
+	return shares, nil
+}
+
+// recoverSecret recovers the secret from threshold shares and verifies its digest
+func recoverSecret(threshold int, shares []rawShare) ([]byte, error) {
+	if threshold == 1 {
+		return shares[0].value, nil
+	}
+
+	sharedSecret := interpolate(shares, secretIndex)
+	digestShare := interpolate(shares, digestIndex)
+	digest := digestShare[:digestLength]
+	randomPart := digestShare[digestLength:]
+
+	if !bytes.Equal(digest, createDigest(randomPart, sharedSecret)) {
+		return nil, ErrInvalidDigest
+	}
+	return sharedSecret, nil
+}
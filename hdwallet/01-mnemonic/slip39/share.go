@@ -0,0 +1,200 @@
+package slip39
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	radixBits           = 10
+	idLengthBits        = 15
+	iterationExpBits    = 4
+	metadataLengthWords = 4 // identifier, extendable flag and iteration exponent plus group and member parameters
+	checksumLengthWords = 3
+	minStrengthBits     = 128
+	maxShareCount       = 16
+	minMnemonicWords    = metadataLengthWords + checksumLengthWords + (minStrengthBits+radixBits-1)/radixBits
+
+	customizationString           = "shamir"
+	customizationStringExtendable = "shamir_extendable"
+)
+
+var (
+	ErrInvalidMnemonicLength = errors.New("Invalid share mnemonic length: must be at least 20 words")
+	ErrInvalidChecksum       = errors.New("Invalid share mnemonic checksum")
+	ErrInvalidPadding        = errors.New("Invalid share mnemonic padding")
+)
+
+// UnknownWordError reports a word which does not exist in the SLIP-39 wordlist.
+// Position is the zero-based index of the word in the share mnemonic.
+type UnknownWordError struct {
+	Word     string
+	Position int
+}
+
+func (e *UnknownWordError) Error() string {
+	return fmt.Sprintf("Unknown share mnemonic word %q at position %d", e.Word, e.Position+1)
+}
+
+// Share is a single SLIP-39 share mnemonic
+type Share struct {
+	Identifier        uint16
+	Extendable        bool
+	IterationExponent int
+	GroupIndex        int
+	GroupThreshold    int
+	GroupCount        int
+	MemberIndex       int
+	MemberThreshold   int
+	Value             []byte
+}
+
+var slip39WordIndex = func() map[string]int {
+	index := make(map[string]int, len(slip39WordList))
+	for i, word := range slip39WordList {
+		index[word] = i
+	}
+	return index
+}()
+
+// ParseShare decodes the share mnemonic and verifies its checksum
+func ParseShare(mnemonic string) (*Share, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) < minMnemonicWords {
+		return nil, ErrInvalidMnemonicLength
+	}
+
+	data := make([]int, len(words))
+	for i, word := range words {
+		index, ok := slip39WordIndex[word]
+		if !ok {
+			return nil, &UnknownWordError{Word: word, Position: i}
+		}
+		data[i] = index
+	}
+
+	// identifier, extendable flag and iteration exponent
+	idExp := data[0]<<radixBits | data[1]
+	extendable := (idExp>>iterationExpBits)&1 == 1
+	if !verifyChecksum(data, extendable) {
+		return nil, ErrInvalidChecksum
+	}
+
+	// group and member parameters, 4 bits each
+	params := data[2]<<radixBits | data[3]
+	share := &Share{
+		Identifier:        uint16(idExp >> (iterationExpBits + 1)),
+		Extendable:        extendable,
+		IterationExponent: idExp & (1<<iterationExpBits - 1),
+		GroupIndex:        params >> 16 & 0xF,
+		GroupThreshold:    params>>12&0xF + 1,
+		GroupCount:        params>>8&0xF + 1,
+		MemberIndex:       params >> 4 & 0xF,
+		MemberThreshold:   params&0xF + 1,
+	}
+	if share.GroupCount < share.GroupThreshold {
+		return nil, errors.New("Invalid share mnemonic: group threshold exceeds the group count")
+	}
+
+	// the share value is left padded with at most 8 zero bits
+	valueWords := data[metadataLengthWords : len(data)-checksumLengthWords]
+	paddingLength := radixBits * len(valueWords) % 16
+	if paddingLength > 8 {
+		return nil, ErrInvalidMnemonicLength
+	}
+	valueLength := (radixBits*len(valueWords) - paddingLength) / 8
+
+	value := new(big.Int)
+	for _, word := range valueWords {
+		value.Lsh(value, radixBits)
+		value.Or(value, big.NewInt(int64(word)))
+	}
+	if value.BitLen() > valueLength*8 {
+		return nil, ErrInvalidPadding
+	}
+	share.Value = value.FillBytes(make([]byte, valueLength))
+
+	return share, nil
+}
+
+// ValidateShare checks words, padding and checksum of the share mnemonic
+func ValidateShare(mnemonic string) error {
+	_, err := ParseShare(mnemonic)
+	return err
+}
+
+// Mnemonic encodes the share as a mnemonic
+func (s *Share) Mnemonic() string {
+	idExp := int(s.Identifier)<<(iterationExpBits+1) | s.IterationExponent
+	if s.Extendable {
+		idExp |= 1 << iterationExpBits
+	}
+	params := s.GroupIndex<<16 | (s.GroupThreshold-1)<<12 | (s.GroupCount-1)<<8 | s.MemberIndex<<4 | (s.MemberThreshold - 1)
+
+	valueWordCount := (len(s.Value)*8 + radixBits - 1) / radixBits
+	data := []int{idExp >> radixBits, idExp & (1<<radixBits - 1), params >> radixBits, params & (1<<radixBits - 1)}
+
+	value := new(big.Int).SetBytes(s.Value)
+	mask := big.NewInt(1<<radixBits - 1)
+	valueData := make([]int, valueWordCount)
+	for i := valueWordCount - 1; i >= 0; i-- {
+		valueData[i] = int(new(big.Int).And(value, mask).Int64())
+		value.Rsh(value, radixBits)
+	}
+	data = append(data, valueData...)
+	data = append(data, createChecksum(data, s.Extendable)...)
+
+	words := make([]string, len(data))
+	for i, index := range data {
+		words[i] = slip39WordList[index]
+	}
+	return strings.Join(words, " ")
+}
+
+var rs1024Generator = [10]int{0xE0E040, 0x1C1C080, 0x3838100, 0x7070200, 0xE0E0009, 0x1C0C2412, 0x38086C24, 0x3090FC48, 0x21B1F890, 0x3F3F120}
+
+// rs1024Polymod computes the Reed-Solomon checksum over GF(1024)
+func rs1024Polymod(values []int) int {
+	chk := 1
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xFFFFF)<<10 ^ v
+		for i := 0; i < 10; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= rs1024Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func customizationValues(extendable bool) []int {
+	customization := customizationString
+	if extendable {
+		customization = customizationStringExtendable
+	}
+
+	values := make([]int, len(customization))
+	for i := range customization {
+		values[i] = int(customization[i])
+	}
+	return values
+}
+
+func createChecksum(data []int, extendable bool) []int {
+	values := append(customizationValues(extendable), data...)
+	values = append(values, make([]int, checksumLengthWords)...)
+	polymod := rs1024Polymod(values) ^ 1
+
+	checksum := make([]int, checksumLengthWords)
+	for i := range checksum {
+		checksum[i] = polymod >> (radixBits * (checksumLengthWords - 1 - i)) & (1<<radixBits - 1)
+	}
+	return checksum
+}
+
+func verifyChecksum(data []int, extendable bool) bool {
+	return rs1024Polymod(append(customizationValues(extendable), data...)) == 1
+}
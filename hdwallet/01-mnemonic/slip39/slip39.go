@@ -0,0 +1,198 @@
+package slip39
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInsufficientShares = errors.New("Insufficient shares: threshold has not been reached")
+	ErrTooManyShares      = errors.New("Too many shares: more groups or members than the threshold were provided")
+)
+
+// MemberGroup describes a group of Count member shares, any Threshold of
+// which recover the group share
+type MemberGroup struct {
+	Threshold int
+	Count     int
+}
+
+// GenerateMnemonics splits the master secret into groups of share mnemonics.
+// Any groupThreshold groups, each with its member threshold of shares,
+// recover the master secret. The master secret is encrypted with the
+// passphrase, and 10000*2^iterationExponent PBKDF2 iterations are spent on it.
+func GenerateMnemonics(groupThreshold int, groups []MemberGroup, masterSecret []byte, passphrase string, extendable bool, iterationExponent int) ([][]string, error) {
+	if len(masterSecret)*8 < minStrengthBits || len(masterSecret)%2 != 0 {
+		return nil, errors.New("Invalid master secret length: must be an even number of bytes and at least 16 bytes")
+	}
+	if groupThreshold < 1 || groupThreshold > len(groups) {
+		return nil, errors.New("Invalid group threshold: must be between 1 and the group count")
+	}
+	if iterationExponent < 0 || iterationExponent >= 1<<iterationExpBits {
+		return nil, errors.New("Invalid iteration exponent: must be between 0 and 15")
+	}
+	for _, group := range groups {
+		if group.Threshold == 1 && group.Count > 1 {
+			return nil, errors.New("Invalid member group: use 1-of-1 instead of several shares with threshold 1")
+		}
+	}
+	err := validatePassphrase(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	identifier, err := randomIdentifier()
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedMasterSecret := encrypt(masterSecret, []byte(passphrase), iterationExponent, identifier, extendable)
+	groupShares, err := splitSecret(groupThreshold, len(groups), encryptedMasterSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	mnemonics := make([][]string, len(groups))
+	for i, group := range groups {
+		memberShares, err := splitSecret(group.Threshold, group.Count, groupShares[i].value)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, memberShare := range memberShares {
+			share := &Share{
+				Identifier:        identifier,
+				Extendable:        extendable,
+				IterationExponent: iterationExponent,
+				GroupIndex:        int(groupShares[i].x),
+				GroupThreshold:    groupThreshold,
+				GroupCount:        len(groups),
+				MemberIndex:       int(memberShare.x),
+				MemberThreshold:   group.Threshold,
+				Value:             memberShare.value,
+			}
+			mnemonics[i] = append(mnemonics[i], share.Mnemonic())
+		}
+	}
+	return mnemonics, nil
+}
+
+// CombineMnemonics recovers the master secret from share mnemonics.
+// Exactly the group threshold of groups must be given, each with exactly
+// its member threshold of shares.
+// The master secret is the seed consumed by key.NewMasterFromSeed.
+func CombineMnemonics(mnemonics []string, passphrase string) ([]byte, error) {
+	if len(mnemonics) == 0 {
+		return nil, ErrInsufficientShares
+	}
+	err := validatePassphrase(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]*Share, len(mnemonics))
+	for i, mnemonic := range mnemonics {
+		shares[i], err = ParseShare(mnemonic)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	encryptedMasterSecret, err := recoverEncryptedMasterSecret(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	first := shares[0]
+	return decrypt(encryptedMasterSecret, []byte(passphrase), first.IterationExponent, first.Identifier, first.Extendable), nil
+}
+
+// recoverEncryptedMasterSecret recovers each group share from its members
+// and then the encrypted master secret from the group shares
+func recoverEncryptedMasterSecret(shares []*Share) ([]byte, error) {
+	first := shares[0]
+	groups := make(map[int][]*Share)
+	var groupOrder []int
+	for _, share := range shares {
+		if share.Identifier != first.Identifier || share.Extendable != first.Extendable || share.IterationExponent != first.IterationExponent {
+			return nil, errors.New("Invalid set of shares: all shares must belong to the same master secret")
+		}
+		if share.GroupThreshold != first.GroupThreshold || share.GroupCount != first.GroupCount {
+			return nil, errors.New("Invalid set of shares: all shares must have the same group parameters")
+		}
+		if len(share.Value) != len(first.Value) {
+			return nil, errors.New("Invalid set of shares: all shares must have the same length")
+		}
+
+		if _, ok := groups[share.GroupIndex]; !ok {
+			groupOrder = append(groupOrder, share.GroupIndex)
+		}
+		groups[share.GroupIndex] = append(groups[share.GroupIndex], share)
+	}
+
+	// exactly the threshold of groups, each with exactly its member threshold of shares
+	if len(groups) < first.GroupThreshold {
+		return nil, fmt.Errorf("%w: %d of %d groups provided", ErrInsufficientShares, len(groups), first.GroupThreshold)
+	}
+	if len(groups) > first.GroupThreshold {
+		return nil, fmt.Errorf("%w: %d groups provided, threshold is %d", ErrTooManyShares, len(groups), first.GroupThreshold)
+	}
+
+	var groupShares []rawShare
+	for _, groupIndex := range groupOrder {
+		members := groups[groupIndex]
+		memberThreshold := members[0].MemberThreshold
+
+		var memberShares []rawShare
+		seen := make(map[int][]byte)
+		for _, member := range members {
+			if member.MemberThreshold != memberThreshold {
+				return nil, errors.New("Invalid set of shares: member threshold differs within a group")
+			}
+			// the same share entered twice is ignored
+			if value, ok := seen[member.MemberIndex]; ok {
+				if !bytes.Equal(value, member.Value) {
+					return nil, errors.New("Invalid set of shares: different shares with the same member index")
+				}
+				continue
+			}
+			seen[member.MemberIndex] = member.Value
+			memberShares = append(memberShares, rawShare{byte(member.MemberIndex), member.Value})
+		}
+
+		if len(memberShares) < memberThreshold {
+			return nil, fmt.Errorf("%w: %d of %d shares provided for group %d", ErrInsufficientShares, len(memberShares), memberThreshold, groupIndex+1)
+		}
+		if len(memberShares) > memberThreshold {
+			return nil, fmt.Errorf("%w: %d shares provided for group %d, threshold is %d", ErrTooManyShares, len(memberShares), groupIndex+1, memberThreshold)
+		}
+		groupSecret, err := recoverSecret(memberThreshold, memberShares)
+		if err != nil {
+			return nil, err
+		}
+		groupShares = append(groupShares, rawShare{byte(groupIndex), groupSecret})
+	}
+	return recoverSecret(first.GroupThreshold, groupShares)
+}
+
+func randomIdentifier() (uint16, error) {
+	b := make([]byte, 2)
+	_, err := rand.Read(b)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(b) & (1<<idLengthBits - 1), nil
+}
+
+// validatePassphrase accepts printable ASCII characters only
+func validatePassphrase(passphrase string) error {
+	for _, c := range passphrase {
+		if c < 32 || c > 126 {
+			return errors.New("Invalid passphrase: must contain only printable ASCII characters")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package slip39
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+)
+
+// SLIP-39 test vectors (vectors.json) with passphrase "TREZOR", an empty
+// master secret marks an invalid set of mnemonics
+var slip39Vectors = []struct {
+	description  string
+	mnemonics    []string
+	masterSecret string
+}{
+	{
+		"Valid mnemonic without sharing (128 bits)",
+		[]string{"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard"},
+		"bb54aac4b89dc868ba37d9cc21b2cece",
+	},
+	{
+		"Mnemonic with invalid checksum (128 bits)",
+		[]string{"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision kidney"},
+		"",
+	},
+	{
+		"Mnemonic with invalid padding (128 bits)",
+		[]string{"duckling enlarge academic academic email result length solution fridge kidney coal piece deal husband erode duke ajar music cargo fitness"},
+		"",
+	},
+	{
+		"Basic sharing 2-of-3 (128 bits)",
+		[]string{
+			"shadow pistol academic always adequate wildlife fancy gross oasis cylinder mustang wrist rescue view short owner flip making coding armed",
+			"shadow pistol academic acid actress prayer class unknown daughter sweater depict flip twice unkind craft early superior advocate guest smoking",
+		},
+		"b43ceb7e57a0ea8766221624d01b0864",
+	},
+	{
+		"Basic sharing 2-of-3 (128 bits)",
+		[]string{"shadow pistol academic always adequate wildlife fancy gross oasis cylinder mustang wrist rescue view short owner flip making coding armed"},
+		"",
+	},
+	{
+		"Mnemonics with different identifiers (128 bits)",
+		[]string{
+			"adequate smoking academic acid debut wine petition glen cluster slow rhyme slow simple epidemic rumor junk tracks treat olympic tolerate",
+			"adequate stay academic agency agency formal party ting frequent learn upstairs remember smear leaf damage anatomy ladle market hush corner",
+		},
+		"",
+	},
+	{
+		"Mnemonics with different iteration exponents (128 bits)",
+		[]string{
+			"peasant leaves academic acid desert exact olympic math alive axle trial tackle drug deny decent smear dominant desert bucket remind",
+			"peasant leader academic agency cultural blessing percent network envelope medal junk primary human pumps jacket fragment payroll ticket evoke voice",
+		},
+		"",
+	},
+	{
+		"Mnemonics with mismatching group thresholds (128 bits)",
+		[]string{
+			"liberty category beard echo animal fawn temple briefing math username various wolf aviation fancy visual holy thunder yelp helpful payment",
+			"liberty category beard email beyond should fancy romp founder easel pink holy hairy romp loyalty material victim owner toxic custody",
+			"liberty category academic easy being hazard crush diminish oral lizard reaction cluster force dilemma deploy force club veteran expect photo",
+		},
+		"",
+	},
+	{
+		"Mnemonics with greater group threshold than group counts (128 bits)",
+		[]string{
+			"music husband acrobat acid artist finance center either graduate swimming object bike medical clothes station aspect spider maiden bulb welcome",
+			"music husband acrobat agency advance hunting bike corner density careful material civil evil tactics remind hawk discuss hobo voice rainbow",
+			"music husband beard academic black tricycle clock mayor estimate level photo episode exclude ecology papa source amazing salt verify divorce",
+		},
+		"",
+	},
+	{
+		"Mnemonics with duplicate member indices (128 bits)",
+		[]string{
+			"device stay academic always dive coal antenna adult black exceed stadium herald advance soldier busy dryer daughter evaluate minister laser",
+			"device stay academic always dwarf afraid robin gravity crunch adjust soul branch walnut coastal dream costume scholar mortgage mountain pumps",
+		},
+		"",
+	},
+	{
+		"Mnemonics with mismatching member thresholds (128 bits)",
+		[]string{
+			"hour painting academic academic device formal evoke guitar random modern justice filter withdraw trouble identify mailman insect general cover oven",
+			"hour painting academic agency artist again daisy capital beaver fiber much enjoy suitable symbolic identify photo editor romp float echo",
+		},
+		"",
+	},
+	{
+		"Mnemonics giving an invalid digest (128 bits)",
+		[]string{
+			"guilt walnut academic acid deliver remove equip listen vampire tactics nylon rhythm failure husband fatigue alive blind enemy teaspoon rebound",
+			"guilt walnut academic agency brave hamster hobo declare herd taste alpha slim criminal mild arcade formal romp branch pink ambition",
+		},
+		"",
+	},
+	{
+		"Insufficient number of groups (128 bits, case 1)",
+		[]string{"eraser senior beard romp adorn nuclear spill corner cradle style ancient family general leader ambition exchange unusual garlic promise voice"},
+		"",
+	},
+	{
+		"Insufficient number of groups (128 bits, case 2)",
+		[]string{
+			"eraser senior ceramic snake clay various huge numb argue hesitate auction category timber browser greatest hanger petition script leaf pickup",
+			"eraser senior ceramic shaft dynamic become junior wrist silver peasant force math alto coal amazing segment yelp velvet image paces",
+		},
+		"",
+	},
+	{
+		"Threshold number of groups and members in each group (128 bits, case 2)",
+		[]string{
+			"eraser senior decision smug corner ruin rescue cubic angel tackle skin skunk program roster trash rumor slush angel flea amazing",
+			"eraser senior beard romp adorn nuclear spill corner cradle style ancient family general leader ambition exchange unusual garlic promise voice",
+			"eraser senior decision roster beard treat identify grumpy salt index fake aviation theater cubic bike cause research dragon emphasis counter",
+		},
+		"7c3397a292a5941682d7a4ae2d898d11",
+	},
+	{
+		"Threshold number of groups and members in each group (128 bits, case 3)",
+		[]string{
+			"eraser senior beard romp adorn nuclear spill corner cradle style ancient family general leader ambition exchange unusual garlic promise voice",
+			"eraser senior acrobat romp bishop medical gesture pumps secret alive ultimate quarter priest subject class dictate spew material endless market",
+		},
+		"7c3397a292a5941682d7a4ae2d898d11",
+	},
+	{
+		"Valid mnemonic without sharing (256 bits)",
+		[]string{"theory painting academic academic armed sweater year military elder discuss acne wildlife boring employer fused large satoshi bundle carbon diagnose anatomy hamster leaves tracks paces beyond phantom capital marvel lips brave detect luck"},
+		"989baf9dcaad5b10ca33dfd8cc75e42477025dce88ae83e75a230086a0e00e92",
+	},
+	{
+		"Mnemonic with invalid checksum (256 bits)",
+		[]string{"theory painting academic academic armed sweater year military elder discuss acne wildlife boring employer fused large satoshi bundle carbon diagnose anatomy hamster leaves tracks paces beyond phantom capital marvel lips facility obtain sister"},
+		"",
+	},
+	{
+		"Basic sharing 2-of-3 (256 bits)",
+		[]string{
+			"humidity disease academic always aluminum jewelry energy woman receiver strategy amuse duckling lying evidence network walnut tactics forget hairy rebound impulse brother survive clothes stadium mailman rival ocean reward venture always armed unwrap",
+			"humidity disease academic agency actress jacket gross physics cylinder solution fake mortgage benefit public busy prepare sharp friar change work slow purchase ruler again tricycle involve viral wireless mixture anatomy desert cargo upgrade",
+		},
+		"c938b319067687e990e05e0da0ecce1278f75ff58d9853f19dcaeed5de104aae",
+	},
+	{
+		"Mnemonic with insufficient length",
+		[]string{"junk necklace academic academic acne isolate join hesitate lunar roster dough calcium chemical ladybug amount mobile glasses verify cylinder"},
+		"",
+	},
+	{
+		"Mnemonic with invalid master secret length",
+		[]string{"fraction necklace academic academic award teammate mouse regular testify coding building member verdict purchase blind camera duration email prepare spirit quarter"},
+		"",
+	},
+	{
+		"Valid extendable mnemonic without sharing (128 bits)",
+		[]string{"testify swimming academic academic column loyalty smear include exotic bedroom exotic wrist lobe cover grief golden smart junior estimate learn"},
+		"1679b4516e0ee5954351d288a838f45e",
+	},
+	{
+		"Extendable basic sharing 2-of-3 (128 bits)",
+		[]string{
+			"enemy favorite academic acid cowboy phrase havoc level response walnut budget painting inside trash adjust froth kitchen learn tidy punish",
+			"enemy favorite academic always academic sniff script carpet romp kind promise scatter center unfair training emphasis evening belong fake enforce",
+		},
+		"48b1a4b80b8c209ad42c33672bdaa428",
+	},
+	{
+		"Extendable basic sharing 2-of-3 (256 bits)",
+		[]string{
+			"western apart academic always artist resident briefing sugar woman oven coding club ajar merit pecan answer prisoner artist fraction amount desktop mild false necklace muscle photo wealthy alpha category unwrap spew losing making",
+			"western apart academic acid answer ancient auction flip image penalty oasis beaver multiple thunder problem switch alive heat inherit superior teaspoon explain blanket pencil numb lend punish endless aunt garlic humidity kidney observe",
+		},
+		"8dc652d6d6cd370d8c963141f6d79ba440300f25c467302c1d966bff8f62300d",
+	},
+}
+
+func TestCombineMnemonicsVectors(t *testing.T) {
+	for _, vector := range slip39Vectors {
+		masterSecret, err := CombineMnemonics(vector.mnemonics, "TREZOR")
+		if vector.masterSecret == "" {
+			if err == nil {
+				t.Fatalf("%s: expected error", vector.description)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", vector.description, err)
+		}
+		if hex.EncodeToString(masterSecret) != vector.masterSecret {
+			t.Fatalf("%s: got %x, want %s", vector.description, masterSecret, vector.masterSecret)
+		}
+	}
+
+	// a group beyond the group threshold is rejected
+	extraGroup := append(append([]string(nil), slip39Vectors[15].mnemonics...), slip39Vectors[14].mnemonics[0], slip39Vectors[14].mnemonics[2])
+	_, err := CombineMnemonics(extraGroup, "TREZOR")
+	if !errors.Is(err, ErrTooManyShares) {
+		t.Fatalf("got %v, want %v", err, ErrTooManyShares)
+	}
+
+	// the same share entered twice is accepted
+	repeated := append(append([]string(nil), slip39Vectors[3].mnemonics...), slip39Vectors[3].mnemonics[0])
+	masterSecret, err := CombineMnemonics(repeated, "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(masterSecret) != slip39Vectors[3].masterSecret {
+		t.Fatalf("got %x, want %s", masterSecret, slip39Vectors[3].masterSecret)
+	}
+}
+
+func TestGenerateMnemonics(t *testing.T) {
+	masterSecret, _ := hex.DecodeString("c8cd6caa5ea16cd1f05bb8b1ac2ebd7c1edaa1c7fdf2c2e05e4e71b2d11a1e1d")
+	groups := []MemberGroup{{1, 1}, {2, 3}, {3, 5}}
+	mnemonics, err := GenerateMnemonics(2, groups, masterSecret, "TREZOR", true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, group := range groups {
+		if len(mnemonics[i]) != group.Count {
+			t.Fatalf("group %d: got %d shares, want %d", i, len(mnemonics[i]), group.Count)
+		}
+		for _, mnemonic := range mnemonics[i] {
+			err = ValidateShare(mnemonic)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// first group together with two members of the second group
+	shares := []string{mnemonics[0][0], mnemonics[1][2], mnemonics[1][0]}
+	recovered, err := CombineMnemonics(shares, "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(recovered) != hex.EncodeToString(masterSecret) {
+		t.Fatalf("got %x, want %x", recovered, masterSecret)
+	}
+
+	// three members of the third group are not enough without another group
+	_, err = CombineMnemonics(mnemonics[2][:3], "TREZOR")
+	if !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("got %v, want %v", err, ErrInsufficientShares)
+	}
+
+	// a member beyond the member threshold is rejected
+	_, err = CombineMnemonics(append(shares, mnemonics[1][1]), "TREZOR")
+	if !errors.Is(err, ErrTooManyShares) {
+		t.Fatalf("got %v, want %v", err, ErrTooManyShares)
+	}
+
+	// a group below its member threshold is not complete
+	_, err = CombineMnemonics([]string{mnemonics[0][0], mnemonics[2][0], mnemonics[2][1]}, "TREZOR")
+	if !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("got %v, want %v", err, ErrInsufficientShares)
+	}
+
+	// the recovered master secret is a BIP-32 seed
+	_, err = key.NewMasterFromSeed(recovered)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseShare(t *testing.T) {
+	mnemonic := slip39Vectors[0].mnemonics[0]
+	share, err := ParseShare(mnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if share.Mnemonic() != mnemonic {
+		t.Fatalf("got %q, want %q", share.Mnemonic(), mnemonic)
+	}
+
+	err = ValidateShare("duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision kidney")
+	if !errors.Is(err, ErrInvalidChecksum) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidChecksum)
+	}
+
+	err = ValidateShare("duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keybord")
+	var unknownWord *UnknownWordError
+	if !errors.As(err, &unknownWord) || unknownWord.Position != 19 {
+		t.Fatalf("got %v, want UnknownWordError at position 19", err)
+	}
+}
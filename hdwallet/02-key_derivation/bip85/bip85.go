@@ -0,0 +1,178 @@
+package bip85
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/01-mnemonic/mnemonic"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+)
+
+const (
+	purpose = 83696968
+
+	appBIP39          = 39
+	appWIF            = 2
+	appXPRV           = 32
+	appHex            = 128169
+	appPasswordBase64 = 707764
+	appPasswordBase85 = 707785
+)
+
+var hmacKey = []byte("bip-entropy-from-k")
+
+// languageCodes maps mnemonic languages to BIP-85 language codes
+var languageCodes = map[mnemonic.Language]uint32{
+	mnemonic.English:            0,
+	mnemonic.Japanese:           1,
+	mnemonic.Korean:             2,
+	mnemonic.Spanish:            3,
+	mnemonic.ChineseSimplified:  4,
+	mnemonic.ChineseTraditional: 5,
+	mnemonic.French:             6,
+	mnemonic.Italian:            7,
+	mnemonic.Czech:              8,
+	mnemonic.Portuguese:         9,
+}
+
+// DeriveEntropy derives the private key at m/83696968'/{indexes}' from the
+// master key and returns HMAC-SHA512("bip-entropy-from-k", k) of it
+func DeriveEntropy(master *key.ExtendedKey, indexes ...uint32) ([]byte, error) {
+	if master == nil || !master.IsPrivate() {
+		return nil, errors.New("Invalid master key: BIP-85 requires a private extended key")
+	}
+
+	path := fmt.Sprintf("m/%d'", purpose)
+	for _, index := range indexes {
+		if index >= key.HardenedOffset {
+			return nil, errors.New("Invalid index range: BIP-85 indexes are hardened and must be below 0x80000000")
+		}
+		path += fmt.Sprintf("/%d'", index)
+	}
+
+	derived, err := master.DerivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	defer derived.Zero()
+
+	h := hmac.New(sha512.New, hmacKey)
+	h.Write(derived.PrivateKey().Serialize())
+	return h.Sum(nil), nil
+}
+
+// DeriveMnemonic derives a child mnemonic of 12, 15, 18, 21 or 24 words
+// at m/83696968'/39'/{language}'/{words}'/{index}'
+func DeriveMnemonic(master *key.ExtendedKey, language mnemonic.Language, words int, index uint32) (string, error) {
+	code, ok := languageCodes[language]
+	if !ok {
+		return "", errors.New("Unsupported language: " + string(language))
+	}
+	wordlist, err := mnemonic.GetWordlist(language)
+	if err != nil {
+		return "", err
+	}
+	if words%3 != 0 || words < 12 || words > 24 {
+		return "", mnemonic.ErrInvalidMnemonicLength
+	}
+
+	entropy, err := DeriveEntropy(master, appBIP39, code, uint32(words), index)
+	if err != nil {
+		return "", err
+	}
+
+	// every 3 words carry 4 bytes of entropy
+	return mnemonic.NewMnemonicWithWordlist(entropy[:words/3*4], wordlist)
+}
+
+// DeriveWIF derives a compressed mainnet WIF private key at m/83696968'/2'/{index}'
+func DeriveWIF(master *key.ExtendedKey, index uint32) (string, error) {
+	entropy, err := DeriveEntropy(master, appWIF, index)
+	if err != nil {
+		return "", err
+	}
+	privateKey, err := key.PrivateKeyFromByte(entropy[:32])
+	if err != nil {
+		return "", err
+	}
+
+	return key.EncodeWIF(privateKey, true, false), nil
+}
+
+// DeriveXPRV derives a mainnet master extended private key at m/83696968'/32'/{index}'.
+// The first 32 bytes of entropy are the chain code and the last 32 bytes the private key.
+func DeriveXPRV(master *key.ExtendedKey, index uint32) (string, error) {
+	entropy, err := DeriveEntropy(master, appXPRV, index)
+	if err != nil {
+		return "", err
+	}
+	privateKey, err := key.PrivateKeyFromByte(entropy[32:])
+	if err != nil {
+		return "", err
+	}
+
+	return key.NewExtendedKey(privateKey, privateKey.PublicKey(), entropy[:32], 0, true).Serialize(), nil
+}
+
+// DeriveHex derives numBytes (16 to 64) of entropy at m/83696968'/128169'/{numBytes}'/{index}'
+func DeriveHex(master *key.ExtendedKey, numBytes int, index uint32) (string, error) {
+	if numBytes < 16 || numBytes > 64 {
+		return "", errors.New("Invalid number of bytes: must be between 16 and 64")
+	}
+
+	entropy, err := DeriveEntropy(master, appHex, uint32(numBytes), index)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(entropy[:numBytes]), nil
+}
+
+// DerivePasswordBase64 derives a base64 password of length 20 to 86
+// at m/83696968'/707764'/{length}'/{index}'
+func DerivePasswordBase64(master *key.ExtendedKey, length int, index uint32) (string, error) {
+	if length < 20 || length > 86 {
+		return "", errors.New("Invalid password length: must be between 20 and 86")
+	}
+
+	entropy, err := DeriveEntropy(master, appPasswordBase64, uint32(length), index)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(entropy)[:length], nil
+}
+
+// DerivePasswordBase85 derives a base85 password of length 10 to 80
+// at m/83696968'/707785'/{length}'/{index}'
+func DerivePasswordBase85(master *key.ExtendedKey, length int, index uint32) (string, error) {
+	if length < 10 || length > 80 {
+		return "", errors.New("Invalid password length: must be between 10 and 80")
+	}
+
+	entropy, err := DeriveEntropy(master, appPasswordBase85, uint32(length), index)
+	if err != nil {
+		return "", err
+	}
+	return base85Encode(entropy)[:length], nil
+}
+
+const base85Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~"
+
+// base85Encode encodes 4-byte chunks with the RFC 1924 alphabet
+func base85Encode(input []byte) string {
+	result := make([]byte, 0, len(input)/4*5)
+	for i := 0; i+4 <= len(input); i += 4 {
+		chunk := binary.BigEndian.Uint32(input[i:])
+		var encoded [5]byte
+		for j := 4; j >= 0; j-- {
+			encoded[j] = base85Alphabet[chunk%85]
+			chunk /= 85
+		}
+		result = append(result, encoded[:]...)
+	}
+	return string(result)
+}
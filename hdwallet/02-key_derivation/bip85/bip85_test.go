@@ -0,0 +1,122 @@
+package bip85
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/01-mnemonic/mnemonic"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+)
+
+// master key of the BIP-85 test vectors
+// xprv9s21ZrQH143K2LBWUUQRFXhucrQqBpKdRRxNVq2zBqsx8HVqFk2uYo8kmbaLLHRdqtQpUm98uKfu3vca1LqdGhUtyoFnCNkfmXRyPXLjbKb
+var (
+	masterChainCodeHex  = "1b67969d1ec69bdfeeae43213da8460ba34b92d0788c8f7bfcfa44906e8a589c"
+	masterPrivateKeyHex = "3f15e5d852dc2e9ba5e9fe189a8dd2e1547badef5b563bbe6579fc6807d80ed9"
+)
+
+func testMaster(t *testing.T) *key.ExtendedKey {
+	chainCode, _ := hex.DecodeString(masterChainCodeHex)
+	privateKeyByte, _ := hex.DecodeString(masterPrivateKeyHex)
+	privateKey, err := key.PrivateKeyFromByte(privateKeyByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key.NewExtendedKey(privateKey, privateKey.PublicKey(), chainCode, 0, true)
+}
+
+func TestDeriveEntropy(t *testing.T) {
+	master := testMaster(t)
+
+	vectors := []struct {
+		index   uint32
+		entropy string
+	}{
+		{0, "efecfbccffea313214232d29e71563d941229afb4338c21f9517c41aaa0d16f00b83d2a09ef747e7a64e8e2bd5a14869e693da66ce94ac2da570ab7ee48618f7"},
+		{1, "70c6e3e8ebee8dc4c0dbba66076819bb8c09672527c4277ca8729532ad711872218f826919f6b67218adde99018a6df9095ab2b58d803b5b93ec9802085a690e"},
+	}
+	for _, vector := range vectors {
+		entropy, err := DeriveEntropy(master, 0, vector.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(entropy) != vector.entropy {
+			t.Fatalf("m/83696968'/0'/%d': got %x, want %s", vector.index, entropy, vector.entropy)
+		}
+	}
+}
+
+func TestDeriveMnemonic(t *testing.T) {
+	master := testMaster(t)
+
+	vectors := []struct {
+		words    int
+		mnemonic string
+	}{
+		{12, "girl mad pet galaxy egg matter matrix prison refuse sense ordinary nose"},
+		{18, "near account window bike charge season chef number sketch tomorrow excuse sniff circle vital hockey outdoor supply token"},
+		{24, "puppy ocean match cereal symbol another shed magic wrap hammer bulb intact gadget divorce twin tonight reason outdoor destroy simple truth cigar social volcano"},
+	}
+	for _, vector := range vectors {
+		phrase, err := DeriveMnemonic(master, mnemonic.English, vector.words, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if phrase != vector.mnemonic {
+			t.Fatalf("%d words: got %q, want %q", vector.words, phrase, vector.mnemonic)
+		}
+	}
+
+	phrase, err := DeriveMnemonic(master, mnemonic.Japanese, 12, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wordlist, err := mnemonic.DetectLanguage(phrase)
+	if err != nil || wordlist.Language() != mnemonic.Japanese {
+		t.Fatalf("got %v, %v", wordlist, err)
+	}
+}
+
+func TestDeriveApplications(t *testing.T) {
+	master := testMaster(t)
+
+	wif, err := DeriveWIF(master, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wif != "Kzyv4uF39d4Jrw2W7UryTHwZr1zQVNk4dAFyqE6BuMrMh1Za7uhp" {
+		t.Fatalf("WIF: got %s", wif)
+	}
+
+	xprv, err := DeriveXPRV(master, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xprv != "xprv9s21ZrQH143K2srSbCSg4m4kLvPMzcWydgmKEnMmoZUurYuBuYG46c6P71UGXMzmriLzCCBvKQWBUv3vPB3m1SATMhp3uEjXHJ42jFg7myX" {
+		t.Fatalf("XPRV: got %s", xprv)
+	}
+
+	hexEntropy, err := DeriveHex(master, 64, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hexEntropy != "492db4698cf3b73a5a24998aa3e9d7fa96275d85724a91e71aa2d645442f878555d078fd1f1f67e368976f04137b1f7a0d19232136ca50c44614af72b5582a5c" {
+		t.Fatalf("HEX: got %s", hexEntropy)
+	}
+
+	password, err := DerivePasswordBase64(master, 21, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if password != "dKLoepugzdVJvdL56ogNV" {
+		t.Fatalf("PWD BASE64: got %s", password)
+	}
+
+	password, err = DerivePasswordBase85(master, 12, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if password != "_s`{TW89)i4`" {
+		t.Fatalf("PWD BASE85: got %s", password)
+	}
+}
@@ -0,0 +1,21 @@
+package key
+
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// ECDH returns the 32-byte x coordinate of k * publicKey, the shared secret
+// between the owner of k and the owner of publicKey
+func (k *PrivateKey) ECDH(publicKey *PublicKey) ([]byte, error) {
+	parsedPublicKey, err := btcec.ParsePubKey(publicKey.Serialize())
+	if err != nil {
+		return nil, err
+	}
+
+	var scalar btcec.ModNScalar
+	scalar.Set(&k.scalar)
+	privateKey := btcec.PrivKeyFromScalar(&scalar)
+	defer privateKey.Zero()
+
+	return btcec.GenerateSharedSecret(privateKey, parsedPublicKey), nil
+}
@@ -0,0 +1,128 @@
+package key
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// ECIES parameters of Ethereum devp2p: concatenation KDF with SHA-256,
+// AES-128-CTR and HMAC-SHA256
+const (
+	eciesKeyLength = 16
+	eciesIVLength  = aes.BlockSize
+	eciesMACLength = sha256.Size
+	eciesOverhead  = unCompressedPublicKeySize + eciesIVLength + eciesMACLength
+)
+
+var ErrInvalidMessage = errors.New("Invalid message: MAC verification failed")
+
+// EncryptECIES encrypts the message to the public key. s1 is mixed into the
+// KDF and s2 into the MAC, both may be nil. The result is
+// ephemeral public key (65 bytes) || IV || cipher text || HMAC-SHA256.
+func EncryptECIES(publicKey *PublicKey, message []byte, s1 []byte, s2 []byte) ([]byte, error) {
+	ephemeralKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	defer ephemeralKey.Zero()
+
+	parsedPublicKey, err := btcec.ParsePubKey(publicKey.Serialize())
+	if err != nil {
+		return nil, err
+	}
+	shared := btcec.GenerateSharedSecret(ephemeralKey, parsedPublicKey)
+	encryptionKey, macKey := eciesKeys(shared, s1)
+	zeroBytes(shared)
+
+	iv := make([]byte, eciesIVLength)
+	_, err = rand.Read(iv)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := make([]byte, eciesIVLength+len(message))
+	copy(encrypted, iv)
+	cipher.NewCTR(block, iv).XORKeyStream(encrypted[eciesIVLength:], message)
+
+	result := make([]byte, 0, eciesOverhead+len(message))
+	result = append(result, ephemeralKey.PubKey().SerializeUncompressed()...)
+	result = append(result, encrypted...)
+	result = append(result, eciesMAC(macKey, encrypted, s2)...)
+	return result, nil
+}
+
+// DecryptECIES verifies the MAC and decrypts a message encrypted to the public key of k
+func (k *PrivateKey) DecryptECIES(ciphertext []byte, s1 []byte, s2 []byte) ([]byte, error) {
+	if len(ciphertext) < eciesOverhead {
+		return nil, errors.New("Invalid message length: shorter than the ECIES overhead")
+	}
+	if ciphertext[0] != 0x04 {
+		return nil, errors.New("Invalid ephemeral public key: must be uncompressed")
+	}
+
+	ephemeralKey, err := PublicKeyFromByte(ciphertext[:unCompressedPublicKeySize])
+	if err != nil {
+		return nil, err
+	}
+	shared, err := k.ECDH(ephemeralKey)
+	if err != nil {
+		return nil, err
+	}
+	encryptionKey, macKey := eciesKeys(shared, s1)
+	zeroBytes(shared)
+
+	encrypted := ciphertext[unCompressedPublicKeySize : len(ciphertext)-eciesMACLength]
+	mac := ciphertext[len(ciphertext)-eciesMACLength:]
+	if !hmac.Equal(mac, eciesMAC(macKey, encrypted, s2)) {
+		return nil, ErrInvalidMessage
+	}
+
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	message := make([]byte, len(encrypted)-eciesIVLength)
+	cipher.NewCTR(block, encrypted[:eciesIVLength]).XORKeyStream(message, encrypted[eciesIVLength:])
+	return message, nil
+}
+
+// eciesKeys derives the AES key and the MAC key SHA256(Km) from the shared secret
+func eciesKeys(shared []byte, s1 []byte) ([]byte, []byte) {
+	derived := concatKDF(shared, s1, 2*eciesKeyLength)
+	macKey := sha256.Sum256(derived[eciesKeyLength:])
+	return derived[:eciesKeyLength], macKey[:]
+}
+
+// concatKDF is the NIST SP 800-56 concatenation key derivation function with SHA-256
+func concatKDF(z []byte, s1 []byte, length int) []byte {
+	counter := make([]byte, 4)
+	var derived []byte
+	h := sha256.New()
+	for i := uint32(1); len(derived) < length; i++ {
+		binary.BigEndian.PutUint32(counter, i)
+		h.Reset()
+		h.Write(counter)
+		h.Write(z)
+		h.Write(s1)
+		derived = h.Sum(derived)
+	}
+	return derived[:length]
+}
+
+// eciesMAC returns HMAC-SHA256(macKey, IV || cipher text || s2)
+func eciesMAC(macKey []byte, encrypted []byte, s2 []byte) []byte {
+	h := hmac.New(sha256.New, macKey)
+	h.Write(encrypted)
+	h.Write(s2)
+	return h.Sum(nil)
+}
@@ -0,0 +1,129 @@
+package key
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+)
+
+func newTestKeys(t *testing.T) (*PrivateKey, *PrivateKey) {
+	seed, _ := hex.DecodeString(bip32Vector1Seed)
+	master, _ := NewMasterFromSeed(seed)
+	alice, err := master.DerivePath("m/0'/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := master.DerivePath("m/0'/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return alice.PrivateKey(), bob.PrivateKey()
+}
+
+func TestECDH(t *testing.T) {
+	alice, bob := newTestKeys(t)
+
+	aliceShared, err := alice.ECDH(bob.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobShared, err := bob.ECDH(alice.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aliceShared) != 32 || !bytes.Equal(aliceShared, bobShared) {
+		t.Fatalf("shared secrets differ: %x, %x", aliceShared, bobShared)
+	}
+
+	// go-ethereum derives the same secret
+	aliceECDSA, _ := crypto.ToECDSA(alice.Serialize())
+	bobECDSA, _ := crypto.UnmarshalPubkey(bob.PublicKey().SerializeUnCompressed())
+	shared, err := ecies.ImportECDSA(aliceECDSA).GenerateShared(ecies.ImportECDSAPublic(bobECDSA), 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(shared, aliceShared) {
+		t.Fatalf("got %x, want %x", aliceShared, shared)
+	}
+}
+
+func TestECIES(t *testing.T) {
+	alice, bob := newTestKeys(t)
+	message := []byte("invoice #42: 0.01 BTC")
+	s1 := []byte("kdf shared info")
+	s2 := []byte("mac shared info")
+
+	ciphertext, err := EncryptECIES(bob.PublicKey(), message, s1, s2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ciphertext) != eciesOverhead+len(message) {
+		t.Fatalf("got length %d, want %d", len(ciphertext), eciesOverhead+len(message))
+	}
+	decrypted, err := bob.DecryptECIES(ciphertext, s1, s2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, message) {
+		t.Fatalf("got %q, want %q", decrypted, message)
+	}
+
+	// only the recipient can decrypt, with the same shared info
+	_, err = alice.DecryptECIES(ciphertext, s1, s2)
+	if err != ErrInvalidMessage {
+		t.Fatalf("wrong key: got %v, want %v", err, ErrInvalidMessage)
+	}
+	_, err = bob.DecryptECIES(ciphertext, s1, nil)
+	if err != ErrInvalidMessage {
+		t.Fatalf("wrong s2: got %v, want %v", err, ErrInvalidMessage)
+	}
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[unCompressedPublicKeySize+eciesIVLength] ^= 0x01
+	_, err = bob.DecryptECIES(tampered, s1, s2)
+	if err != ErrInvalidMessage {
+		t.Fatalf("tampered cipher text: got %v, want %v", err, ErrInvalidMessage)
+	}
+	_, err = bob.DecryptECIES(ciphertext[:eciesOverhead-1], s1, s2)
+	if err == nil {
+		t.Fatal("truncated message: expected error")
+	}
+}
+
+func TestECIESGoEthereumCompatibility(t *testing.T) {
+	_, bob := newTestKeys(t)
+	bobECDSA, _ := crypto.ToECDSA(bob.Serialize())
+	bobECIES := ecies.ImportECDSA(bobECDSA)
+	message := []byte("encrypted backup")
+
+	for _, shared := range [][]byte{nil, []byte("shared")} {
+		// go-ethereum decrypts our messages
+		ciphertext, err := EncryptECIES(bob.PublicKey(), message, shared, shared)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decrypted, err := bobECIES.Decrypt(ciphertext, shared, shared)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted, message) {
+			t.Fatalf("got %q, want %q", decrypted, message)
+		}
+
+		// and we decrypt messages of go-ethereum
+		ciphertext, err = ecies.Encrypt(rand.Reader, &bobECIES.PublicKey, message, shared, shared)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decrypted, err = bob.DecryptECIES(ciphertext, shared, shared)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted, message) {
+			t.Fatalf("got %q, want %q", decrypted, message)
+		}
+	}
+}
@@ -2,8 +2,6 @@ package key
 
 import (
 	"errors"
-	"strconv"
-	"strings"
 )
 
 type ExtendedKey struct {
@@ -11,6 +9,9 @@ type ExtendedKey struct {
 	pubKey *PublicKey
 	chainCode []byte
 	depth uint8
+	parentFingerprint []byte
+	childNumber uint32
+	version KeyVersion
 	isPrivate bool
 }
 
@@ -24,31 +25,38 @@ func NewExtendedKey(pirvKey *PrivateKey, pubKey *PublicKey, chainCode []byte, de
 	}
 }
 
+// DerivePath derives the key at the path. An absolute path (m/...) must be
+// applied to the master key, a relative path (0/1) to any key.
 func (e *ExtendedKey) DerivePath(path string) (*ExtendedKey, error) {
-	paths := strings.Split(path, "/")
+	derivationPath, err := ParseDerivationPath(path)
+	if err != nil {
+		return &ExtendedKey{}, err
+	}
+
+	return e.DeriveFromPath(derivationPath)
+}
 
-	if paths[0] != "m" {
-		return &ExtendedKey{}, errors.New("Follow bip-44 standards derivation path.")
+func (e *ExtendedKey) DeriveFromPath(path DerivationPath) (*ExtendedKey, error) {
+	if !path.Relative && e.depth != 0 {
+		return &ExtendedKey{}, errors.New("Invalid derivation path: absolute path must be derived from the master key")
 	}
 
-	key := e
-	for _, p := range paths[1:] {
-		pUint64, err := strconv.ParseInt(string(p[0]), 10, 64)
-		if err != nil {
-			return &ExtendedKey{}, err 
-		}
+	// an empty path returns a copy, so that zeroing the result keeps the receiver intact
+	if len(path.Indexes) == 0 {
+		return e.clone(), nil
+	}
 
-		var index uint32
-		if p[len(p)-1] == byte('\'') {
-			index = uint32(pUint64) + HardenedOffset
-		} else {
-			index = uint32(pUint64)
+	// intermediate keys are wiped as soon as their child is derived
+	key := e
+	for _, index := range path.Indexes {
+		child, err := key.Derive(index)
+		if key != e {
+			key.Zero()
 		}
-
-		key, err = key.Derive(index)
 		if err != nil {
 			return &ExtendedKey{}, err
 		}
+		key = child
 	}
 
 	return key, nil
@@ -63,8 +71,12 @@ func (e *ExtendedKey) Derive(index uint32) (*ExtendedKey, error) {
 
 		return &ExtendedKey{
 			privKey: childPrivateKey,
+			pubKey: childPrivateKey.PublicKey(),
 			chainCode: childChainCode,
 			depth: e.depth+1,
+			parentFingerprint: e.Fingerprint(),
+			childNumber: index,
+			version: e.version,
 			isPrivate: true,
 		}, nil
 	} else {
@@ -80,7 +92,85 @@ func (e *ExtendedKey) Derive(index uint32) (*ExtendedKey, error) {
 			pubKey: childPublicKey,
 			chainCode: childChainCode,
 			depth: e.depth+1,
+			parentFingerprint: e.Fingerprint(),
+			childNumber: index,
+			version: e.version,
 			isPrivate: false,
 		}, nil
 	}
-}
\ No newline at end of file
+}
+
+// PrivateKey returns nil for a public extended key
+func (e *ExtendedKey) PrivateKey() *PrivateKey {
+	return e.privKey
+}
+
+func (e *ExtendedKey) PublicKey() *PublicKey {
+	if e.pubKey == nil && e.privKey != nil {
+		return e.privKey.PublicKey()
+	}
+	return e.pubKey
+}
+
+// Neuter returns the public extended key without any private material.
+// Only non-hardened children can be derived from it.
+func (e *ExtendedKey) Neuter() *ExtendedKey {
+	return &ExtendedKey{
+		pubKey: e.PublicKey(),
+		chainCode: append([]byte(nil), e.chainCode...),
+		depth: e.depth,
+		parentFingerprint: e.parentFingerprint,
+		childNumber: e.childNumber,
+		version: e.version,
+		isPrivate: false,
+	}
+}
+
+// clone returns a copy that shares no private material with the key
+func (e *ExtendedKey) clone() *ExtendedKey {
+	key := *e
+	if e.privKey != nil {
+		privKey := *e.privKey
+		key.privKey = &privKey
+	}
+	key.chainCode = append([]byte(nil), e.chainCode...)
+	return &key
+}
+
+// Zero overwrites the private key and chain code. The key must not be used afterwards.
+func (e *ExtendedKey) Zero() {
+	if e.privKey != nil {
+		e.privKey.Zero()
+	}
+	zeroBytes(e.chainCode)
+}
+
+// ChainCode returns a copy of the chain code
+func (e *ExtendedKey) ChainCode() []byte {
+	return append([]byte(nil), e.chainCode...)
+}
+
+func (e *ExtendedKey) Depth() uint8 {
+	return e.depth
+}
+
+func (e *ExtendedKey) IsPrivate() bool {
+	return e.isPrivate
+}
+
+
+// Fingerprint returns the first 4 bytes of HASH160 of the compressed public key
+func (e *ExtendedKey) Fingerprint() []byte {
+	return Hash160(e.PublicKey().Serialize())[:4]
+}
+
+func (e *ExtendedKey) ParentFingerprint() []byte {
+	if e.parentFingerprint == nil {
+		return make([]byte, 4)
+	}
+	return e.parentFingerprint
+}
+
+func (e *ExtendedKey) ChildNumber() uint32 {
+	return e.childNumber
+}
@@ -0,0 +1,19 @@
+package key
+
+import (
+	"crypto/sha256"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/00-encoding/base58"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// ErrInvalidChecksum is returned for a corrupted Base58Check key
+var ErrInvalidChecksum = base58.ErrInvalidChecksum
+
+// Hash160 returns RIPEMD160(SHA256(data)), the hash of Bitcoin addresses and key fingerprints
+func Hash160(data []byte) []byte {
+	sha256Hash := sha256.Sum256(data)
+	ripemd160Hasher := ripemd160.New()
+	ripemd160Hasher.Write(sha256Hash[:])
+	return ripemd160Hasher.Sum(nil)
+}
@@ -26,12 +26,14 @@ func NewMasterFromSeed(seed []byte) (*ExtendedKey, error) {
 	masterChainCodeByte := h512[len(h512)/2:]
 
 	masterPrivateKey, err := PrivateKeyFromByte(masterPrivateKeyByte)
+	zeroBytes(masterPrivateKeyByte)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ExtendedKey{
 		privKey: masterPrivateKey,
+		pubKey: masterPrivateKey.PublicKey(),
 		chainCode: masterChainCodeByte,
 		depth: 0,
 		isPrivate: true,
@@ -75,29 +77,34 @@ func DerivePublicKey(parentPublicKey *PublicKey, parentChainCode []byte, index u
 func DerivePrivateKey(parentPrivateKey *PrivateKey, parentChainCode []byte, index uint32) (*PrivateKey, []byte, error) {
 	var data []byte
 	if index >= HardenedOffset {
-		data = GenerateHardenedKeyData(parentPrivateKey.Serialize(), index)
+		privateKeyByte := parentPrivateKey.Serialize()
+		data = GenerateHardenedKeyData(privateKeyByte, index)
+		zeroBytes(privateKeyByte)
 	} else {
 		data = GenerateNormalKeyData(parentPrivateKey.PublicKey().Serialize(), index)
 	}
 	h := hmac.New(sha512.New, parentChainCode)
 	h.Write(data)
 	I := h.Sum(nil)
+	zeroBytes(data)
 
 	IL := I[:32]
 	IR := I[32:]
-	ILInt := new(big.Int).SetBytes(IL)
-	if ILInt.Cmp(n) >= 0 {
+	defer zeroBytes(IL)
+
+	// child key = IL + parent key (mod n) in constant time
+	var childKey btcec.ModNScalar
+	defer childKey.Zero()
+	overflow := childKey.SetByteSlice(IL)
+	if overflow {
 		return nil, nil, errors.New("Invalid index value: should proceed with the next value for index")
 	}
-
-	childKeyInt := new(big.Int)
-	childKeyInt.Add(parentPrivateKey.D, ILInt)
-	childKeyInt.Mod(childKeyInt, n)
-	if childKeyInt.Cmp(new(big.Int).SetInt64(0)) == 0 {
+	childKey.Add(&parentPrivateKey.scalar)
+	if childKey.IsZero() {
 		return nil, nil, errors.New("Invalid index value: should proceed with the next value for index")
 	}
 
-	childPrivateKey, err := PrivateKeyFromByte(childKeyInt.FillBytes(make([]byte, privateKeySize)))
+	childPrivateKey, err := privateKeyFromScalar(&childKey)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -118,4 +125,10 @@ func GenerateNormalKeyData(publicKeyByte []byte, index uint32) []byte {
 	copy(data, publicKeyByte)
 	binary.BigEndian.PutUint32(data[33:], index)
 	return data
-}
\ No newline at end of file
+}
+// zeroBytes overwrites secret bytes once they are no longer needed
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -1,8 +1,10 @@
 package key
 
 import (
+	"encoding/hex"
 	"testing"
 	"log"
+	"strings"
 	
 	"github.com/boxwood-zip/learning-blockchain/hdwallet/01-mnemonic/mnemonic"
 )
@@ -17,4 +19,187 @@ func TestKeyDerivation(t *testing.T) {
 
 	addressKey, _ := masterKey.DerivePath("m/44'/0'/0'/0/0")
 	log.Println("addressKey.privKey: ", addressKey.privKey.Hex())
-}
\ No newline at end of file
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		relative bool
+		indexes  []uint32
+		formatted string
+	}{
+		{"m", false, nil, "m"},
+		{"m/44'/60'/0'/0/15", false, []uint32{HardenedOffset + 44, HardenedOffset + 60, HardenedOffset, 0, 15}, "m/44'/60'/0'/0/15"},
+		{"m/84h/0H/2147483647'", false, []uint32{HardenedOffset + 84, HardenedOffset, HardenedOffset + 2147483647}, "m/84'/0'/2147483647'"},
+		{"0/1000000000", true, []uint32{0, 1000000000}, "0/1000000000"},
+	}
+	for _, test := range tests {
+		path, err := ParseDerivationPath(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path.Relative != test.relative || len(path.Indexes) != len(test.indexes) {
+			t.Fatalf("%s: got %+v", test.path, path)
+		}
+		for i := range path.Indexes {
+			if path.Indexes[i] != test.indexes[i] {
+				t.Fatalf("%s: got %+v", test.path, path)
+			}
+		}
+		if path.String() != test.formatted {
+			t.Fatalf("%s: got %s, want %s", test.path, path.String(), test.formatted)
+		}
+	}
+
+	for _, invalid := range []string{"", "m/", "m//0", "m/2147483648", "m/2147483648'", "m/-1", "m/+1", "m/1''", "m/0x10", "m/ 1", "n/0", "m/1/"} {
+		_, err := ParseDerivationPath(invalid)
+		if err == nil {
+			t.Fatalf("%q: expected error", invalid)
+		}
+	}
+
+	// a hardened marker without an index
+	for _, invalid := range []string{"m/'", "m/0/h", "H"} {
+		_, err := ParseDerivationPath(invalid)
+		if err == nil || !strings.Contains(err.Error(), "missing index") {
+			t.Fatalf("%q: got %v, want missing index error", invalid, err)
+		}
+	}
+}
+
+func TestDerivePath(t *testing.T) {
+	seed, _ := hex.DecodeString(bip32Vector2Seed)
+	master, _ := NewMasterFromSeed(seed)
+
+	key, err := master.DerivePath("m/0/2147483647'/1/2147483646'/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j"
+	if key.Serialize() != want {
+		t.Fatalf("got %s, want %s", key.Serialize(), want)
+	}
+
+	// relative path continues from a non-master key
+	account, _ := master.DerivePath("m/0/2147483647h")
+	key, err = account.DerivePath("1/2147483646h/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Serialize() != want {
+		t.Fatalf("got %s, want %s", key.Serialize(), want)
+	}
+
+	_, err = account.DerivePath("m/1")
+	if err == nil {
+		t.Fatal("absolute path from a non-master key: expected error")
+	}
+}
+
+func TestNeuter(t *testing.T) {
+	seed, _ := hex.DecodeString(bip32Vector1Seed)
+	master, _ := NewMasterFromSeed(seed)
+
+	account, err := master.DerivePath("m/0'/1/2'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.PublicKey() == nil || account.pubKey == nil {
+		t.Fatal("derived private key must carry its public key")
+	}
+
+	neutered := account.Neuter()
+	if neutered.IsPrivate() || neutered.PrivateKey() != nil {
+		t.Fatal("neutered key must not hold private material")
+	}
+	want := "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5"
+	if neutered.Serialize() != want {
+		t.Fatalf("got %s, want %s", neutered.Serialize(), want)
+	}
+
+	// watch-only derivation matches the public key of private derivation
+	watchOnly, err := ParseWatchOnlyKey(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicChild, err := watchOnly.DerivePath("2/1000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateChild, _ := account.DerivePath("2/1000000000")
+	if publicChild.PublicKey().Hex() != privateChild.PublicKey().Hex() {
+		t.Fatalf("got %s, want %s", publicChild.PublicKey().Hex(), privateChild.PublicKey().Hex())
+	}
+	if publicChild.Serialize() != "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy" {
+		t.Fatalf("got %s", publicChild.Serialize())
+	}
+
+	_, err = watchOnly.Derive(HardenedOffset)
+	if err == nil {
+		t.Fatal("hardened derivation from a public key: expected error")
+	}
+	_, err = ParseWatchOnlyKey(account.Serialize())
+	if err == nil {
+		t.Fatal("private key as watch-only key: expected error")
+	}
+}
+
+func TestPrivateKeyRange(t *testing.T) {
+	tests := []struct {
+		privateKeyHex string
+		valid         bool
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000001", true},
+		{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", true},
+		{"0000000000000000000000000000000000000000000000000000000000000000", false},
+		{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", false},
+		{"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", false},
+	}
+	for _, test := range tests {
+		privateKeyBytes, _ := hex.DecodeString(test.privateKeyHex)
+		privateKey, err := PrivateKeyFromByte(privateKeyBytes)
+		if (err == nil) != test.valid {
+			t.Fatalf("%s: got error %v, want valid %v", test.privateKeyHex, err, test.valid)
+		}
+		if test.valid && privateKey.Hex() != "0x"+test.privateKeyHex {
+			t.Fatalf("got %s, want 0x%s", privateKey.Hex(), test.privateKeyHex)
+		}
+	}
+}
+
+func TestZero(t *testing.T) {
+	seed, _ := hex.DecodeString(bip32Vector1Seed)
+	master, _ := NewMasterFromSeed(seed)
+	account, _ := master.DerivePath("m/0'/1")
+	neutered := account.Neuter()
+	wantPub := neutered.Serialize()
+
+	privateKey := account.PrivateKey()
+	serialized := privateKey.Serialize()
+	chainCode := account.ChainCode()
+	account.Zero()
+
+	if privateKey.Hex() != "0x"+hex.EncodeToString(make([]byte, 32)) {
+		t.Fatalf("private key not zeroed: %s", privateKey.Hex())
+	}
+	if hex.EncodeToString(account.ChainCode()) != hex.EncodeToString(make([]byte, 32)) {
+		t.Fatalf("chain code not zeroed: %x", account.ChainCode())
+	}
+	// copies handed out earlier are not affected
+	if hex.EncodeToString(serialized) == hex.EncodeToString(make([]byte, 32)) {
+		t.Fatal("serialized copy must not share memory with the key")
+	}
+	if hex.EncodeToString(chainCode) == hex.EncodeToString(make([]byte, 32)) {
+		t.Fatal("chain code copy must not share memory with the key")
+	}
+	if neutered.Serialize() != wantPub {
+		t.Fatalf("neutered key changed after zeroing: %s", neutered.Serialize())
+	}
+
+	// zeroing a copy with another version keeps the original intact
+	wantPriv := master.Serialize()
+	master.WithVersion(BitcoinTestnet).Zero()
+	if master.Serialize() != wantPriv {
+		t.Fatalf("master key changed after zeroing its copy: %s", master.Serialize())
+	}
+}
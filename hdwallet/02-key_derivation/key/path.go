@@ -0,0 +1,88 @@
+package key
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DerivationPath is a parsed BIP-32 path such as m/44'/60'/0'/0/15.
+// A relative path (e.g. 0/15) is derived from the key it is applied to,
+// while an absolute path starts at the master key.
+type DerivationPath struct {
+	Relative bool
+	Indexes  []uint32
+}
+
+// ParseDerivationPath parses a path whose segments are decimal indexes below
+// 2^31, hardened by a trailing ', h or H
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	segments := strings.Split(strings.TrimSpace(path), "/")
+
+	var derivationPath DerivationPath
+	if segments[0] == "m" {
+		segments = segments[1:]
+	} else {
+		derivationPath.Relative = true
+		if segments[0] == "" && len(segments) == 1 {
+			return DerivationPath{}, fmt.Errorf("Invalid derivation path %q: empty path", path)
+		}
+	}
+
+	for i, segment := range segments {
+		index, err := parsePathSegment(segment)
+		if err != nil {
+			return DerivationPath{}, fmt.Errorf("Invalid derivation path %q at segment %d: %v", path, i+1, err)
+		}
+		derivationPath.Indexes = append(derivationPath.Indexes, index)
+	}
+
+	return derivationPath, nil
+}
+
+func parsePathSegment(segment string) (uint32, error) {
+	if segment == "" {
+		return 0, fmt.Errorf("empty segment")
+	}
+
+	hardened := false
+	if last := segment[len(segment)-1]; last == '\'' || last == 'h' || last == 'H' {
+		hardened = true
+		segment = segment[:len(segment)-1]
+	}
+	if segment == "" {
+		return 0, fmt.Errorf("missing index")
+	}
+
+	for _, c := range segment {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("%q is not a decimal index", segment)
+		}
+	}
+	index, err := strconv.ParseUint(segment, 10, 32)
+	if err != nil || index >= HardenedOffset {
+		return 0, fmt.Errorf("index %s exceeds maximum value %d", segment, HardenedOffset-1)
+	}
+
+	if hardened {
+		return uint32(index) + HardenedOffset, nil
+	}
+	return uint32(index), nil
+}
+
+// String formats the path with ' as hardened marker
+func (p DerivationPath) String() string {
+	segments := make([]string, 0, len(p.Indexes)+1)
+	if !p.Relative {
+		segments = append(segments, "m")
+	}
+
+	for _, index := range p.Indexes {
+		if index >= HardenedOffset {
+			segments = append(segments, strconv.FormatUint(uint64(index-HardenedOffset), 10)+"'")
+		} else {
+			segments = append(segments, strconv.FormatUint(uint64(index), 10))
+		}
+	}
+	return strings.Join(segments, "/")
+}
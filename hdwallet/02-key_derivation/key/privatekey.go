@@ -2,18 +2,20 @@ package key
 
 import (
 	"errors"
-	"encoding/binary"
 	"encoding/hex"
-	"math/big"
-	
+
 	"github.com/btcsuite/btcd/btcec/v2"
 )
 
 const privateKeySize = 32
 
+// PrivateKey keeps the secret as a scalar modulo the curve order, so additions
+// during derivation and tweaking run in constant time. Computing the public key
+// is not constant time: btcec only offers a variable-time base multiplication.
+// Call Zero once the key is no longer needed.
 type PrivateKey struct {
 	pubKey *PublicKey
-	D *big.Int
+	scalar btcec.ModNScalar
 }
 
 func ValidatePrivateKeyByte(privateKeyByte []byte) error {
@@ -25,8 +27,11 @@ func ValidatePrivateKeyByte(privateKeyByte []byte) error {
 		return errors.New("Invalid private key length: must be 32 bytes")
 	}
 
-	if binary.BigEndian.Uint32(privateKeyByte) == 0 {
-		return errors.New("Invalid private key value: must be at least 10")
+	var scalar btcec.ModNScalar
+	overflow := scalar.SetByteSlice(privateKeyByte)
+	defer scalar.Zero()
+	if overflow || scalar.IsZero() {
+		return errors.New("Invalid private key value: must be between 1 and n-1")
 	}
 
 	return nil
@@ -38,25 +43,47 @@ func PrivateKeyFromByte(privateKeyByte []byte) (*PrivateKey, error) {
 		return nil, err
 	}
 
-	var D *big.Int
-	D = new(big.Int).SetBytes(privateKeyByte)
-	x, y := btcec.S256().ScalarBaseMult(D.FillBytes(make([]byte, privateKeySize)))
-	compressedPublicKey, err := NewPublicKey(x, y)
+	var scalar btcec.ModNScalar
+	defer scalar.Zero()
+	scalar.SetByteSlice(privateKeyByte)
+	return privateKeyFromScalar(&scalar)
+}
+
+// privateKeyFromScalar copies the scalar into a new private key.
+// Known exception to constant-time handling: the public key comes from the
+// variable-time ScalarBaseMultNonConst, as btcec has no constant-time variant.
+func privateKeyFromScalar(scalar *btcec.ModNScalar) (*PrivateKey, error) {
+	privateKey := &PrivateKey{}
+	privateKey.scalar.Set(scalar)
+
+	var point btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&privateKey.scalar, &point)
+	point.ToAffine()
+	pubKey := btcec.NewPublicKey(&point.X, &point.Y)
+	compressedPublicKey, err := NewPublicKey(pubKey.X(), pubKey.Y())
 	if err != nil {
 		return nil, err
 	}
+	privateKey.pubKey = compressedPublicKey
 
-	return &PrivateKey{compressedPublicKey, D}, nil
+	return privateKey, nil
 }
 
+// Serialize returns a copy of the 32-byte big-endian private key
 func (k *PrivateKey) Serialize() []byte {
-	return k.D.FillBytes(make([]byte, privateKeySize))
+	privateKeyByte := k.scalar.Bytes()
+	return privateKeyByte[:]
 }
 
 func (k *PrivateKey) Hex() string {
-	return "0x" + hex.EncodeToString(k.D.FillBytes(make([]byte, privateKeySize)))
+	return "0x" + hex.EncodeToString(k.Serialize())
 }
 
 func (k *PrivateKey) PublicKey() *PublicKey {
 	return k.pubKey
-}
\ No newline at end of file
+}
+
+// Zero overwrites the secret scalar. The key must not be used afterwards.
+func (k *PrivateKey) Zero() {
+	k.scalar.Zero()
+}
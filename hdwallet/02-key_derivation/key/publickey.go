@@ -73,6 +73,9 @@ func PublicKeyFromByte(publicKeyByte []byte) (*PublicKey, error) {
 	}
 
 	parsedPublicKey, err := btcec.ParsePubKey(publicKeyByte)
+	if err != nil {
+		return nil, err
+	}
 	x := parsedPublicKey.ToECDSA().X 
 	y := parsedPublicKey.ToECDSA().Y
 
@@ -111,8 +114,8 @@ func (k *PublicKey) Hex() string {
 
 func (k *PublicKey) ToECDSA() *ecdsa.PublicKey {
 	return &ecdsa.PublicKey{
-		btcec.S256(),
-		k.x,
-		k.y,
+		Curve: btcec.S256(),
+		X: k.x,
+		Y: k.y,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,83 @@
+package key
+
+import (
+	"crypto/rand"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+const (
+	xOnlyPublicKeySize   = 32
+	schnorrSignatureSize = 64
+)
+
+// SerializeXOnly returns the 32-byte x coordinate used by BIP-340 and Taproot
+func (k *PublicKey) SerializeXOnly() []byte {
+	return k.compressed[1:]
+}
+
+// PublicKeyFromXOnly returns the public key with even y for a 32-byte x coordinate
+func PublicKeyFromXOnly(xOnly []byte) (*PublicKey, error) {
+	if len(xOnly) != xOnlyPublicKeySize {
+		return nil, errors.New("Invalid x-only public key length: must be 32 bytes")
+	}
+	parsedPublicKey, err := schnorr.ParsePubKey(xOnly)
+	if err != nil {
+		return nil, err
+	}
+	return NewPublicKey(parsedPublicKey.X(), parsedPublicKey.Y())
+}
+
+// SignSchnorr signs a 32-byte hash as defined in BIP-340. auxRand is the
+// 32-byte auxiliary randomness mixed into the nonce, random when nil.
+func (k *PrivateKey) SignSchnorr(hash []byte, auxRand []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, errors.New("Invalid hash length: must be 32 bytes")
+	}
+
+	var aux [32]byte
+	if auxRand == nil {
+		_, err := rand.Read(aux[:])
+		if err != nil {
+			return nil, err
+		}
+	} else if len(auxRand) != 32 {
+		return nil, errors.New("Invalid auxiliary randomness length: must be 32 bytes")
+	} else {
+		copy(aux[:], auxRand)
+	}
+
+	var scalar btcec.ModNScalar
+	scalar.Set(&k.scalar)
+	privateKey := btcec.PrivKeyFromScalar(&scalar)
+	defer privateKey.Zero()
+
+	signature, err := schnorr.Sign(privateKey, hash, schnorr.CustomNonce(aux))
+	if err != nil {
+		return nil, err
+	}
+	return signature.Serialize(), nil
+}
+
+// VerifySchnorr verifies a BIP-340 signature of a 32-byte hash against the x-only key
+func (k *PublicKey) VerifySchnorr(hash []byte, signature []byte) bool {
+	return VerifySchnorr(k.SerializeXOnly(), hash, signature)
+}
+
+// VerifySchnorr verifies a BIP-340 signature against a 32-byte x-only public key
+func VerifySchnorr(xOnly []byte, hash []byte, signature []byte) bool {
+	if len(xOnly) != xOnlyPublicKeySize || len(hash) != 32 || len(signature) != schnorrSignatureSize {
+		return false
+	}
+	publicKey, err := schnorr.ParsePubKey(xOnly)
+	if err != nil {
+		return false
+	}
+	parsedSignature, err := schnorr.ParseSignature(signature)
+	if err != nil {
+		return false
+	}
+	return parsedSignature.Verify(hash, publicKey)
+}
@@ -0,0 +1,198 @@
+package key
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// bip340Vectors are the test vectors of BIP-340
+var bip340Vectors = []struct {
+	secretKey string
+	publicKey string
+	auxRand   string
+	message   string
+	signature string
+	valid     bool
+}{
+	{"0000000000000000000000000000000000000000000000000000000000000003", "F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9", "0000000000000000000000000000000000000000000000000000000000000000", "0000000000000000000000000000000000000000000000000000000000000000", "E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0", true},
+	{"B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF", "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659", "0000000000000000000000000000000000000000000000000000000000000001", "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89", "6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A", true},
+	{"C90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74020BBEA63B14E5C9", "DD308AFEC5777E13121FA72B9CC1B7CC0139715309B086C960E18FD969774EB8", "C87AA53824B4D7AE2EB035A2B5BBBCCC080E76CDC6D1692C4B0B62D798E6D906", "7E2D58D8B3BCDF1ABADEC7829054F90DDA9805AAB56C77333024B9D0A508B75C", "5831AAEED7B44BB74E5EAB94BA9D4294C49BCF2A60728D8B4C200F50DD313C1BAB745879A5AD954A72C45A91C3A51D3C7ADEA98D82F8481E0E1E03674A6F3FB7", true},
+	{"0B432B2677937381AEF05BB02A66ECD012773062CF3FA2549E44F58ED2401710", "25D1DFF95105F5253C4022F628A996AD3A0D95FBF21D468A1B33F8C160D8F517", "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF", "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF", "7EB0509757E246F19449885651611CB965ECC1A187DD51B64FDA1EDC9637D5EC97582B9CB13DB3933705B32BA982AF5AF25FD78881EBB32771FC5922EFC66EA3", true},
+	{"", "D69C3509BB99E412E68B0FE8544E72837DFA30746D8BE2AA65975F29D22DC7B9", "", "4DF3C3F68FCC83B27E9D42C90431A72499F17875C81A599B566C9889B9696703", "00000000000000000000003B78CE563F89A0ED9414F5AA28AD0D96D6795F9C6376AFB1548AF603B3EB45C9F8207DEE1060CB71C04E80F593060B07D28308D7F4", true},
+	// public key not on the curve
+	{"", "EEFDEA4CDB677750A420FEE807EACF21EB9898AE79B9768766E4FAA04A2D4A34", "", "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89", "6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E17776969E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B", false},
+	// has_even_y(R) is false
+	{"", "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659", "", "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89", "FFF97BD5755EEEA420453A14355235D382F6472F8568A18B2F057A14602975563CC27944640AC607CD107AE10923D9EF7A73C643E166BE5EBEAFA34B1AC553E2", false},
+	// negated message
+	{"", "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659", "", "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89", "1FA62E331EDBC21C394792D2AB1100A7B432B013DF3F6FF4F99FCB33E0E1515F28890B3EDB6E7189B630448B515CE4F8622A954CFE545735AAEA5134FCCDB2BD", false},
+	// negated s value
+	{"", "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659", "", "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89", "6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E177769961764B3AA9B2FFCB6EF947B6887A226E8D7C93E00C5ED0C1834FF0D0C2E6DA6", false},
+	// sG - eP is infinite
+	{"", "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659", "", "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89", "0000000000000000000000000000000000000000000000000000000000000000123DDA8328AF9C23A94C1FEECFD123BA4FB73476F0D594DCB65C6425BD186051", false},
+	{"", "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659", "", "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89", "00000000000000000000000000000000000000000000000000000000000000017615FBAF5AE28864013C099742DEADB4DBA87F11AC6754F93780D5A1837CF197", false},
+	// sig[0:32] is not an X coordinate on the curve
+	{"", "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659", "", "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89", "4A298DACAE57395A15D0795DDBFD1DCB564DA82B0F269BC70A74F8220429BA1D69E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B", false},
+	// public key exceeds the field size
+	{"", "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30", "", "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89", "6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E17776969E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B", false},
+}
+
+func TestSchnorr(t *testing.T) {
+	for i, v := range bip340Vectors {
+		publicKey, _ := hex.DecodeString(v.publicKey)
+		message, _ := hex.DecodeString(v.message)
+		signature, _ := hex.DecodeString(v.signature)
+
+		if v.secretKey != "" {
+			secretKey, _ := hex.DecodeString(v.secretKey)
+			auxRand, _ := hex.DecodeString(v.auxRand)
+			privateKey, err := PrivateKeyFromByte(secretKey)
+			if err != nil {
+				t.Fatalf("vector %d: %v", i, err)
+			}
+			if !strings.EqualFold(hex.EncodeToString(privateKey.PublicKey().SerializeXOnly()), v.publicKey) {
+				t.Fatalf("vector %d: got public key %x, want %s", i, privateKey.PublicKey().SerializeXOnly(), v.publicKey)
+			}
+
+			got, err := privateKey.SignSchnorr(message, auxRand)
+			if err != nil {
+				t.Fatalf("vector %d: %v", i, err)
+			}
+			if !strings.EqualFold(hex.EncodeToString(got), v.signature) {
+				t.Fatalf("vector %d: got signature %x, want %s", i, got, v.signature)
+			}
+		}
+
+		if VerifySchnorr(publicKey, message, signature) != v.valid {
+			t.Fatalf("vector %d: got valid %v, want %v", i, !v.valid, v.valid)
+		}
+	}
+}
+
+func TestSignSchnorrRandomAux(t *testing.T) {
+	secretKey, _ := hex.DecodeString("B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF")
+	privateKey, _ := PrivateKeyFromByte(secretKey)
+	message := make([]byte, 32)
+
+	signature, err := privateKey.SignSchnorr(message, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !privateKey.PublicKey().VerifySchnorr(message, signature) {
+		t.Fatal("signature with random auxiliary data does not verify")
+	}
+
+	xOnlyKey, err := PublicKeyFromXOnly(privateKey.PublicKey().SerializeXOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !xOnlyKey.VerifySchnorr(message, signature) {
+		t.Fatal("signature does not verify with the lifted x-only key")
+	}
+
+	_, err = privateKey.SignSchnorr(message[:31], nil)
+	if err == nil {
+		t.Fatal("31-byte message: expected error")
+	}
+}
+
+// bip341Vectors are the scriptPubKey vectors of the BIP-341 wallet test vectors
+var bip341Vectors = []struct {
+	internalPubkey string
+	leafScript     string
+	merkleRoot     string
+	tweak          string
+	tweakedPubkey  string
+}{
+	{
+		"d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+		"",
+		"",
+		"b86e7be8f39bab32a6f2c0443abbc210f0edac0e2c53d501b36b64437d9c6c70",
+		"53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+	},
+	{
+		"187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+		"20d85a959b0290bf19bb89ed43c916be835475d013da4b362117393e25a48229b8ac",
+		"5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+		"cbd8679ba636c1110ea247542cfbd964131a6be84f873f7f3b62a777528ed001",
+		"147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+	},
+	{
+		"93478e9488f956df2396be2ce6c5cced75f900dfa18e7dabd2428aae78451820",
+		"20b617298552a72ade070667e86ca63b8f5789a9fe8731ef91202a91c9f3459007ac",
+		"c525714a7f49c28aedbbba78c005931a81c234b2f6c99a73e4d06082adc8bf2b",
+		"6af9e28dbf9d6aaf027696e2598a5b3d056f5fd2355a7fd5a37a0e5008132d30",
+		"e4d810fd50586274face62b8a807eb9719cef49c04177cc6b76a9a4251d5450e",
+	},
+}
+
+func TestTaprootTweak(t *testing.T) {
+	for _, v := range bip341Vectors {
+		xOnly, _ := hex.DecodeString(v.internalPubkey)
+		internalKey, err := PublicKeyFromXOnly(xOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var merkleRoot []byte
+		if v.leafScript != "" {
+			script, _ := hex.DecodeString(v.leafScript)
+			merkleRoot = TapLeafHash(TapscriptLeafVersion, script)
+			if hex.EncodeToString(merkleRoot) != v.merkleRoot {
+				t.Fatalf("got merkle root %x, want %s", merkleRoot, v.merkleRoot)
+			}
+		}
+
+		if hex.EncodeToString(TaprootTweak(internalKey, merkleRoot)) != v.tweak {
+			t.Fatalf("got tweak %x, want %s", TaprootTweak(internalKey, merkleRoot), v.tweak)
+		}
+		outputKey, err := internalKey.TweakTaproot(merkleRoot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(outputKey.SerializeXOnly()) != v.tweakedPubkey {
+			t.Fatalf("got output key %x, want %s", outputKey.SerializeXOnly(), v.tweakedPubkey)
+		}
+	}
+}
+
+func TestTweakTaprootPrivateKey(t *testing.T) {
+	// first key path spending input of the BIP-341 wallet test vectors
+	internalPrivkey, _ := hex.DecodeString("6b973d88838f27366ed61c9ad6367663045cb456e28335c109e30717ae0c6baa")
+	privateKey, _ := PrivateKeyFromByte(internalPrivkey)
+	if hex.EncodeToString(privateKey.PublicKey().SerializeXOnly()) != "d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d" {
+		t.Fatalf("got internal key %x", privateKey.PublicKey().SerializeXOnly())
+	}
+
+	tweaked, err := privateKey.TweakTaproot(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tweaked.Hex() != "0x2405b971772ad26915c8dcdf10f238753a9b837e5f8e6a86fd7c0cce5b7296d9" {
+		t.Fatalf("got tweaked private key %s", tweaked.Hex())
+	}
+
+	// a key-path signature verifies against the tweaked output key
+	outputKey, _ := privateKey.PublicKey().TweakTaproot(nil)
+	message := make([]byte, 32)
+	signature, err := tweaked.SignSchnorr(message, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !outputKey.VerifySchnorr(message, signature) {
+		t.Fatal("key path signature does not verify against the output key")
+	}
+
+	// internal keys with odd y are negated before tweaking
+	for i := 0; i < 8; i++ {
+		seed := make([]byte, 32)
+		seed[31] = byte(i + 1)
+		privateKey, _ := PrivateKeyFromByte(taggedHash("test", seed))
+		tweaked, _ := privateKey.TweakTaproot(nil)
+		outputKey, _ := privateKey.PublicKey().TweakTaproot(nil)
+		if hex.EncodeToString(tweaked.PublicKey().SerializeXOnly()) != hex.EncodeToString(outputKey.SerializeXOnly()) {
+			t.Fatalf("tweaked private key does not match output key for %x", privateKey.Serialize())
+		}
+	}
+}
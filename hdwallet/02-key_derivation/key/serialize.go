@@ -0,0 +1,196 @@
+package key
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/00-encoding/base58"
+)
+
+const serializedKeySize = 78
+
+// KeyVersion holds the version bytes of the private and public serialization
+type KeyVersion struct {
+	Private uint32
+	Public  uint32
+}
+
+var (
+	// BitcoinMainnet serializes as xprv/xpub
+	BitcoinMainnet = KeyVersion{0x0488ADE4, 0x0488B21E}
+	// BitcoinTestnet serializes as tprv/tpub
+	BitcoinTestnet = KeyVersion{0x04358394, 0x043587CF}
+)
+
+// SLIP-132 versions signal the output type of the account
+var (
+	// P2WPKH nested in P2SH (BIP-49) serializes as yprv/ypub and uprv/upub
+	BitcoinMainnetP2WPKHInP2SH = KeyVersion{0x049D7878, 0x049D7CB2}
+	BitcoinTestnetP2WPKHInP2SH = KeyVersion{0x044A4E28, 0x044A5262}
+	// P2WPKH (BIP-84) serializes as zprv/zpub and vprv/vpub
+	BitcoinMainnetP2WPKH = KeyVersion{0x04B2430C, 0x04B24746}
+	BitcoinTestnetP2WPKH = KeyVersion{0x045F18BC, 0x045F1CF6}
+	// multisig P2WSH nested in P2SH serializes as Yprv/Ypub and Uprv/Upub
+	BitcoinMainnetP2WSHInP2SH = KeyVersion{0x0295B005, 0x0295B43F}
+	BitcoinTestnetP2WSHInP2SH = KeyVersion{0x024285B5, 0x024289EF}
+	// multisig P2WSH serializes as Zprv/Zpub and Vprv/Vpub
+	BitcoinMainnetP2WSH = KeyVersion{0x02AA7A99, 0x02AA7ED3}
+	BitcoinTestnetP2WSH = KeyVersion{0x02575048, 0x02575483}
+)
+
+var knownVersions = []KeyVersion{
+	BitcoinMainnet,
+	BitcoinTestnet,
+	BitcoinMainnetP2WPKHInP2SH,
+	BitcoinTestnetP2WPKHInP2SH,
+	BitcoinMainnetP2WPKH,
+	BitcoinTestnetP2WPKH,
+	BitcoinMainnetP2WSHInP2SH,
+	BitcoinTestnetP2WSHInP2SH,
+	BitcoinMainnetP2WSH,
+	BitcoinTestnetP2WSH,
+}
+
+// IsTestnet reports whether the version belongs to Bitcoin testnet
+func (v KeyVersion) IsTestnet() bool {
+	switch v {
+	case BitcoinTestnet, BitcoinTestnetP2WPKHInP2SH, BitcoinTestnetP2WPKH, BitcoinTestnetP2WSHInP2SH, BitcoinTestnetP2WSH:
+		return true
+	}
+	return false
+}
+
+// Version returns the version bytes used to serialize the key, mainnet by default
+func (e *ExtendedKey) Version() KeyVersion {
+	if e.version == (KeyVersion{}) {
+		return BitcoinMainnet
+	}
+	return e.version
+}
+
+// WithVersion returns a copy of the key serialized with the version.
+// Keys derived from the copy inherit the version.
+func (e *ExtendedKey) WithVersion(version KeyVersion) *ExtendedKey {
+	key := e.clone()
+	key.version = version
+	return key
+}
+
+// Serialize encodes the extended key with Base58Check as defined in BIP-32,
+// the private version for a private key and the public version otherwise
+func (e *ExtendedKey) Serialize() string {
+	if e.isPrivate {
+		keyData := append([]byte{0x00}, e.privKey.Serialize()...)
+		return e.serialize(e.Version().Private, keyData)
+	}
+	return e.SerializePublic()
+}
+
+// SerializePublic encodes the public extended key (xpub) even for a private key
+func (e *ExtendedKey) SerializePublic() string {
+	return e.serialize(e.Version().Public, e.PublicKey().Serialize())
+}
+
+// ParseWatchOnlyKey decodes a public extended key and rejects private keys,
+// so that a watch-only service never holds private material
+func ParseWatchOnlyKey(encoded string) (*ExtendedKey, error) {
+	key, err := ParseExtendedKey(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if key.IsPrivate() {
+		return nil, errors.New("Invalid watch-only key: private extended key is not allowed")
+	}
+	return key, nil
+}
+
+func (e *ExtendedKey) String() string {
+	return e.Serialize()
+}
+
+func (e *ExtendedKey) serialize(version uint32, keyData []byte) string {
+	data := make([]byte, 0, serializedKeySize)
+	data = binary.BigEndian.AppendUint32(data, version)
+	data = append(data, e.depth)
+	data = append(data, e.ParentFingerprint()...)
+	data = binary.BigEndian.AppendUint32(data, e.childNumber)
+	data = append(data, e.chainCode...)
+	data = append(data, keyData...)
+	return base58.CheckEncode(data)
+}
+
+// ParseExtendedKey decodes a Base58Check serialized extended key
+func ParseExtendedKey(encoded string) (*ExtendedKey, error) {
+	data, err := base58.CheckDecode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != serializedKeySize {
+		return nil, errors.New("Invalid extended key length: must be 78 bytes")
+	}
+
+	versionBytes := binary.BigEndian.Uint32(data[:4])
+	depth := data[4]
+	parentFingerprint := data[5:9]
+	childNumber := binary.BigEndian.Uint32(data[9:13])
+	chainCode := data[13:45]
+	keyData := data[45:]
+
+	var version KeyVersion
+	var isPrivate bool
+	for _, v := range knownVersions {
+		if versionBytes == v.Private || versionBytes == v.Public {
+			version = v
+			isPrivate = versionBytes == v.Private
+			break
+		}
+	}
+	if version == (KeyVersion{}) {
+		return nil, errors.New("Invalid extended key version: unknown version bytes")
+	}
+
+	if depth == 0 && (!bytes.Equal(parentFingerprint, make([]byte, 4)) || childNumber != 0) {
+		return nil, errors.New("Invalid master key: depth 0 with non-zero parent fingerprint or child number")
+	}
+
+	key := &ExtendedKey{
+		chainCode:         append([]byte(nil), chainCode...),
+		depth:             depth,
+		parentFingerprint: append([]byte(nil), parentFingerprint...),
+		childNumber:       childNumber,
+		version:           version,
+		isPrivate:         isPrivate,
+	}
+
+	if isPrivate {
+		if keyData[0] != 0x00 {
+			return nil, errors.New("Invalid private key data: must be prefixed with 0x00")
+		}
+		key.privKey, err = PrivateKeyFromByte(keyData[1:])
+		if err != nil {
+			return nil, err
+		}
+		key.pubKey = key.privKey.PublicKey()
+	} else {
+		if keyData[0] != 0x02 && keyData[0] != 0x03 {
+			return nil, errors.New("Invalid public key data: must be a compressed public key")
+		}
+		key.pubKey, err = PublicKeyFromByte(keyData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// ConvertExtendedKey re-encodes a serialized extended key with another version,
+// e.g. an xpub exported by one wallet to the zpub expected by another
+func ConvertExtendedKey(encoded string, version KeyVersion) (string, error) {
+	key, err := ParseExtendedKey(encoded)
+	if err != nil {
+		return "", err
+	}
+	return key.WithVersion(version).Serialize(), nil
+}
@@ -0,0 +1,306 @@
+package key
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/00-encoding/base58"
+)
+
+var (
+	bip32Vector1Seed = "000102030405060708090a0b0c0d0e0f"
+	bip32Vector2Seed = "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542"
+	bip32Vector3Seed = "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be"
+	bip32Vector4Seed = "3ddd5602285899a946114506157c7997e5444528f3003f6134712147db19b678"
+)
+
+var bip32Vectors = []struct {
+	seed     string
+	path     []uint32
+	wantPub  string
+	wantPriv string
+}{
+	{
+		bip32Vector1Seed,
+		[]uint32{},
+		"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+	},
+	{
+		bip32Vector1Seed,
+		[]uint32{HardenedOffset},
+		"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+		"xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+	},
+	{
+		bip32Vector1Seed,
+		[]uint32{HardenedOffset, 1},
+		"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+		"xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+	},
+	{
+		bip32Vector1Seed,
+		[]uint32{HardenedOffset, 1, HardenedOffset + 2},
+		"xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+		"xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+	},
+	{
+		bip32Vector1Seed,
+		[]uint32{HardenedOffset, 1, HardenedOffset + 2, 2},
+		"xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+		"xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334",
+	},
+	{
+		bip32Vector1Seed,
+		[]uint32{HardenedOffset, 1, HardenedOffset + 2, 2, 1000000000},
+		"xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+		"xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+	},
+	{
+		bip32Vector2Seed,
+		[]uint32{},
+		"xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+		"xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U",
+	},
+	{
+		bip32Vector2Seed,
+		[]uint32{0},
+		"xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+		"xprv9vHkqa6EV4sPZHYqZznhT2NPtPCjKuDKGY38FBWLvgaDx45zo9WQRUT3dKYnjwih2yJD9mkrocEZXo1ex8G81dwSM1fwqWpWkeS3v86pgKt",
+	},
+	{
+		bip32Vector2Seed,
+		[]uint32{0, HardenedOffset + 2147483647},
+		"xpub6ASAVgeehLbnwdqV6UKMHVzgqAG8Gr6riv3Fxxpj8ksbH9ebxaEyBLZ85ySDhKiLDBrQSARLq1uNRts8RuJiHjaDMBU4Zn9h8LZNnBC5y4a",
+		"xprv9wSp6B7kry3Vj9m1zSnLvN3xH8RdsPP1Mh7fAaR7aRLcQMKTR2vidYEeEg2mUCTAwCd6vnxVrcjfy2kRgVsFawNzmjuHc2YmYRmagcEPdU9",
+	},
+	{
+		bip32Vector2Seed,
+		[]uint32{0, HardenedOffset + 2147483647, 1},
+		"xpub6DF8uhdarytz3FWdA8TvFSvvAh8dP3283MY7p2V4SeE2wyWmG5mg5EwVvmdMVCQcoNJxGoWaU9DCWh89LojfZ537wTfunKau47EL2dhHKon",
+		"xprv9zFnWC6h2cLgpmSA46vutJzBcfJ8yaJGg8cX1e5StJh45BBciYTRXSd25UEPVuesF9yog62tGAQtHjXajPPdbRCHuWS6T8XA2ECKADdw4Ef",
+	},
+	{
+		bip32Vector2Seed,
+		[]uint32{0, HardenedOffset + 2147483647, 1, HardenedOffset + 2147483646},
+		"xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL",
+		"xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc",
+	},
+	{
+		bip32Vector2Seed,
+		[]uint32{0, HardenedOffset + 2147483647, 1, HardenedOffset + 2147483646, 2},
+		"xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+		"xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j",
+	},
+	{
+		bip32Vector3Seed,
+		[]uint32{},
+		"xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13",
+		"xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6",
+	},
+	{
+		bip32Vector3Seed,
+		[]uint32{HardenedOffset},
+		"xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+		"xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L",
+	},
+	{
+		bip32Vector4Seed,
+		[]uint32{},
+		"xpub661MyMwAqRbcGczjuMoRm6dXaLDEhW1u34gKenbeYqAix21mdUKJyuyu5F1rzYGVxyL6tmgBUAEPrEz92mBXjByMRiJdba9wpnN37RLLAXa",
+		"xprv9s21ZrQH143K48vGoLGRPxgo2JNkJ3J3fqkirQC2zVdk5Dgd5w14S7fRDyHH4dWNHUgkvsvNDCkvAwcSHNAQwhwgNMgZhLtQC63zxwhQmRv",
+	},
+	{
+		bip32Vector4Seed,
+		[]uint32{HardenedOffset},
+		"xpub69AUMk3qDBi3uW1sXgjCmVjJ2G6WQoYSnNHyzkmdCHEhSZ4tBok37xfFEqHd2AddP56Tqp4o56AePAgCjYdvpW2PU2jbUPFKsav5ut6Ch1m",
+		"xprv9vB7xEWwNp9kh1wQRfCCQMnZUEG21LpbR9NPCNN1dwhiZkjjeGRnaALmPXCX7SgjFTiCTT6bXes17boXtjq3xLpcDjzEuGLQBM5ohqkao9G",
+	},
+	{
+		bip32Vector4Seed,
+		[]uint32{HardenedOffset, HardenedOffset + 1},
+		"xpub6BJA1jSqiukeaesWfxe6sNK9CCGaujFFSJLomWHprUL9DePQ4JDkM5d88n49sMGJxrhpjazuXYWdMf17C9T5XnxkopaeS7jGk1GyyVziaMt",
+		"xprv9xJocDuwtYCMNAo3Zw76WENQeAS6WGXQ55RCy7tDJ8oALr4FWkuVoHJeHVAcAqiZLE7Je3vZJHxspZdFHfnBEjHqU5hG1Jaj32dVoS6XLT1",
+	},
+}
+
+func TestBIP32Vectors(t *testing.T) {
+	for _, vector := range bip32Vectors {
+		seed, _ := hex.DecodeString(vector.seed)
+		extendedKey, err := NewMasterFromSeed(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, index := range vector.path {
+			extendedKey, err = extendedKey.Derive(index)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if extendedKey.Serialize() != vector.wantPriv {
+			t.Fatalf("%x %v: got %s, want %s", seed, vector.path, extendedKey.Serialize(), vector.wantPriv)
+		}
+		if extendedKey.SerializePublic() != vector.wantPub {
+			t.Fatalf("%x %v: got %s, want %s", seed, vector.path, extendedKey.SerializePublic(), vector.wantPub)
+		}
+
+		for _, encoded := range []string{vector.wantPriv, vector.wantPub} {
+			parsed, err := ParseExtendedKey(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Serialize() != encoded {
+				t.Fatalf("round trip: got %s, want %s", parsed.Serialize(), encoded)
+			}
+		}
+	}
+}
+
+func TestTestnetSerialization(t *testing.T) {
+	seed, _ := hex.DecodeString(bip32Vector1Seed)
+	master, _ := NewMasterFromSeed(seed)
+	extendedKey, err := master.WithVersion(BitcoinTestnet).Derive(HardenedOffset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantPriv := "tprv8bxNLu25VazNnppTCP4fyhyCvBHcYtzE3wr3cwYeL4HA7yf6TLGEUdS4QC1vLT63TkjRssqJe4CvGNEC8DzW5AoPUw56D1Ayg6HY4oy8QZ9"
+	if extendedKey.Serialize() != wantPriv {
+		t.Fatalf("got %s, want %s", extendedKey.Serialize(), wantPriv)
+	}
+	parsed, err := ParseExtendedKey(wantPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Version() != BitcoinTestnet || parsed.Depth() != 1 || parsed.ChildNumber() != HardenedOffset {
+		t.Fatalf("got version %v, depth %d, child %d", parsed.Version(), parsed.Depth(), parsed.ChildNumber())
+	}
+}
+
+func TestParseInvalidExtendedKey(t *testing.T) {
+	// BIP-32 test vector 5
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"pubkey version / prvkey mismatch", "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6LBpB85b3D2yc8sfvZU521AAwdZafEz7mnzBBsz4wKY5fTtTQBm"},
+		{"prvkey version / pubkey mismatch", "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGTQQD3dC4H2D5GBj7vWvSQaaBv5cxi9gafk7NF3pnBju6dwKvH"},
+		{"invalid pubkey prefix 04", "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Txnt3siSujt9RCVYsx4qHZGc62TG4McvMGcAUjeuwZdduYEvFn"},
+		{"invalid prvkey prefix 04", "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGpWnsj83BHtEy5Zt8CcDr1UiRXuWCmTQLxEK9vbz5gPstX92JQ"},
+		{"invalid pubkey prefix 01", "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6N8ZMMXctdiCjxTNq964yKkwrkBJJwpzZS4HS2fxvyYUA4q2Xe4"},
+		{"invalid prvkey prefix 01", "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD9y5gkZ6Eq3Rjuahrv17fEQ3Qen6J"},
+		{"zero depth with non-zero parent fingerprint", "xprv9s2SPatNQ9Vc6GTbVMFPFo7jsaZySyzk7L8n2uqKXJen3KUmvQNTuLh3fhZMBoG3G4ZW1N2kZuHEPY53qmbZzCHshoQnNf4GvELZfqTUrcv"},
+		{"zero depth with non-zero parent fingerprint", "xpub661no6RGEX3uJkY4bNnPcw4URcQTrSibUZ4NqJEw5eBkv7ovTwgiT91XX27VbEXGENhYRCf7hyEbWrR3FewATdCEebj6znwMfQkhRYHRLpJ"},
+		{"zero depth with non-zero index", "xprv9s21ZrQH4r4TsiLvyLXqM9P7k1K3EYhA1kkD6xuquB5i39AU8KF42acDyL3qsDbU9NmZn6MsGSUYZEsuoePmjzsB3eFKSUEh3Gu1N3cqVUN"},
+		{"zero depth with non-zero index", "xpub661MyMwAuDcm6CRQ5N4qiHKrJ39Xe1R1NyfouMKTTWcguwVcfrZJaNvhpebzGerh7gucBvzEQWRugZDuDXjNDRmXzSZe4c7mnTK97pTvGS8"},
+		{"unknown extended key version", "DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHGMQzT7ayAmfo4z3gY5KfbrZWZ6St24UVf2Qgo6oujFktLHdHY4"},
+		{"unknown extended key version", "DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHPmHJiEDXkTiJTVV9rHEBUem2mwVbbNfvT2MTcAqj3nesx8uBf9"},
+		{"private key 0 not in 1..n-1", "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzF93Y5wvzdUayhgkkFoicQZcP3y52uPPxFnfoLZB21Teqt1VvEHx"},
+		{"private key n not in 1..n-1", "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD5SDKr24z3aiUvKr9bJpdrcLg1y3G"},
+		{"invalid pubkey 020000000000000000000000000000000000000000000000000000000000000007", "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Q5JXayek4PRsn35jii4veMimro1xefsM58PgBMrvdYre8QyULY"},
+		{"invalid checksum", "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHL"},
+	}
+	for _, test := range tests {
+		_, err := ParseExtendedKey(test.encoded)
+		if err == nil {
+			t.Fatalf("%s: expected error", test.name)
+		}
+	}
+
+	_, err := ParseExtendedKey(tests[len(tests)-1].encoded)
+	if !errors.Is(err, ErrInvalidChecksum) {
+		t.Fatalf("invalid checksum: got %v, want ErrInvalidChecksum", err)
+	}
+
+	seed, _ := hex.DecodeString(bip32Vector1Seed)
+	master, _ := NewMasterFromSeed(seed)
+	data, _ := base58.CheckDecode(master.Serialize())
+	_, err = ParseExtendedKey(base58.CheckEncode(data[:77]))
+	if err == nil {
+		t.Fatal("truncated: expected error")
+	}
+}
+
+func TestSLIP132Versions(t *testing.T) {
+	// BIP-84 account m/84'/0'/0' of "abandon abandon ... about"
+	seed, _ := hex.DecodeString("5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4")
+	master, _ := NewMasterFromSeed(seed)
+	account := master.WithVersion(BitcoinMainnetP2WPKH)
+	for _, index := range []uint32{HardenedOffset + 84, HardenedOffset, HardenedOffset} {
+		account, _ = account.Derive(index)
+	}
+
+	zprv := "zprvAdG4iTXWBoARxkkzNpNh8r6Qag3irQB8PzEMkAFeTRXxHpbF9z4QgEvBRmfvqWvGp42t42nvgGpNgYSJA9iefm1yYNZKEm7z6qUWCroSQnE"
+	zpub := "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+	if account.Serialize() != zprv {
+		t.Fatalf("got %s, want %s", account.Serialize(), zprv)
+	}
+	if account.SerializePublic() != zpub {
+		t.Fatalf("got %s, want %s", account.SerializePublic(), zpub)
+	}
+
+	parsed, err := ParseExtendedKey(zpub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Version() != BitcoinMainnetP2WPKH || parsed.IsPrivate() {
+		t.Fatalf("got version %v, private %v", parsed.Version(), parsed.IsPrivate())
+	}
+
+	// conversion keeps the key material and only swaps the version bytes
+	xpub, err := ConvertExtendedKey(zpub, BitcoinMainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xpub[:4] != "xpub" {
+		t.Fatalf("got %s", xpub)
+	}
+	for version, prefix := range map[KeyVersion]string{
+		BitcoinMainnetP2WPKHInP2SH: "ypub",
+		BitcoinTestnetP2WPKHInP2SH: "upub",
+		BitcoinTestnetP2WPKH:       "vpub",
+		BitcoinMainnetP2WSHInP2SH:  "Ypub",
+		BitcoinTestnetP2WSHInP2SH:  "Upub",
+		BitcoinMainnetP2WSH:        "Zpub",
+		BitcoinTestnetP2WSH:        "Vpub",
+	} {
+		converted, err := ConvertExtendedKey(xpub, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if converted[:4] != prefix {
+			t.Fatalf("got %s, want prefix %s", converted, prefix)
+		}
+		back, err := ConvertExtendedKey(converted, BitcoinMainnetP2WPKH)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back != zpub {
+			t.Fatalf("got %s, want %s", back, zpub)
+		}
+	}
+
+	// private variants
+	for version, prefix := range map[KeyVersion]string{
+		BitcoinMainnetP2WPKHInP2SH: "yprv",
+		BitcoinTestnetP2WPKHInP2SH: "uprv",
+		BitcoinTestnetP2WPKH:       "vprv",
+		BitcoinMainnetP2WSHInP2SH:  "Yprv",
+		BitcoinTestnetP2WSHInP2SH:  "Uprv",
+		BitcoinMainnetP2WSH:        "Zprv",
+		BitcoinTestnetP2WSH:        "Vprv",
+	} {
+		converted, err := ConvertExtendedKey(zprv, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if converted[:4] != prefix {
+			t.Fatalf("got %s, want prefix %s", converted, prefix)
+		}
+		if version.IsTestnet() != (prefix[0] == 'u' || prefix[0] == 'v' || prefix[0] == 'U' || prefix[0] == 'V') {
+			t.Fatalf("%s: IsTestnet %v", prefix, version.IsTestnet())
+		}
+	}
+}
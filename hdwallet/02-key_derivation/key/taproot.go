@@ -0,0 +1,110 @@
+package key
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// TapscriptLeafVersion is the leaf version of BIP-342 tapscript
+const TapscriptLeafVersion = 0xc0
+
+// taggedHash returns SHA256(SHA256(tag) || SHA256(tag) || data...) as defined in BIP-340
+func taggedHash(tag string, data ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// TapLeafHash returns the hash of a script leaf of a Taproot script tree
+func TapLeafHash(leafVersion byte, script []byte) []byte {
+	return taggedHash("TapLeaf", []byte{leafVersion}, compactSize(uint64(len(script))), script)
+}
+
+// TapBranchHash returns the hash of a branch, the children are sorted first
+func TapBranchHash(a []byte, b []byte) []byte {
+	if string(a) > string(b) {
+		a, b = b, a
+	}
+	return taggedHash("TapBranch", a, b)
+}
+
+// TaprootTweak returns t = hash_TapTweak(x-only internal key || merkle root).
+// The merkle root is nil for a key-path only output such as BIP-86.
+func TaprootTweak(internalKey *PublicKey, merkleRoot []byte) []byte {
+	return taggedHash("TapTweak", internalKey.SerializeXOnly(), merkleRoot)
+}
+
+// TweakTaproot returns the output key Q = P + tG of the internal key P with even y.
+// Its x-only serialization is the witness program of a P2TR output.
+func (k *PublicKey) TweakTaproot(merkleRoot []byte) (*PublicKey, error) {
+	if merkleRoot != nil && len(merkleRoot) != 32 {
+		return nil, errors.New("Invalid merkle root length: must be 32 bytes")
+	}
+
+	var tweak btcec.ModNScalar
+	overflow := tweak.SetByteSlice(TaprootTweak(k, merkleRoot))
+	if overflow {
+		return nil, errors.New("Invalid tweak value: must be less than the curve order")
+	}
+
+	// the internal key is lifted to the point with even y
+	internalPoint, err := btcec.ParseJacobian(append([]byte{0x02}, k.SerializeXOnly()...))
+	if err != nil {
+		return nil, err
+	}
+	var tweakPoint, outputPoint btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&tweak, &tweakPoint)
+	btcec.AddNonConst(&internalPoint, &tweakPoint, &outputPoint)
+	if (outputPoint.X.IsZero() && outputPoint.Y.IsZero()) || outputPoint.Z.IsZero() {
+		return nil, errors.New("Invalid tweak value: output key is the point at infinity")
+	}
+	outputPoint.ToAffine()
+
+	return PublicKeyFromByte(btcec.NewPublicKey(&outputPoint.X, &outputPoint.Y).SerializeCompressed())
+}
+
+// TweakTaproot returns the private key of the output key for key-path spends:
+// d' = d + t if the internal key has even y and d' = n - d + t otherwise
+func (k *PrivateKey) TweakTaproot(merkleRoot []byte) (*PrivateKey, error) {
+	if merkleRoot != nil && len(merkleRoot) != 32 {
+		return nil, errors.New("Invalid merkle root length: must be 32 bytes")
+	}
+
+	var tweak btcec.ModNScalar
+	overflow := tweak.SetByteSlice(TaprootTweak(k.pubKey, merkleRoot))
+	if overflow {
+		return nil, errors.New("Invalid tweak value: must be less than the curve order")
+	}
+
+	var tweaked btcec.ModNScalar
+	defer tweaked.Zero()
+	tweaked.Set(&k.scalar)
+	if k.pubKey.y.Bit(0) == 1 {
+		tweaked.Negate()
+	}
+	tweaked.Add(&tweak)
+	if tweaked.IsZero() {
+		return nil, errors.New("Invalid tweak value: tweaked private key is zero")
+	}
+	return privateKeyFromScalar(&tweaked)
+}
+
+// compactSize encodes a length as a Bitcoin variable length integer
+func compactSize(n uint64) []byte {
+	switch {
+	case n < 0xfd:
+		return []byte{byte(n)}
+	case n <= 0xffff:
+		return []byte{0xfd, byte(n), byte(n >> 8)}
+	case n <= 0xffffffff:
+		return []byte{0xfe, byte(n), byte(n >> 8), byte(n >> 16), byte(n >> 24)}
+	}
+	return []byte{0xff, byte(n), byte(n >> 8), byte(n >> 16), byte(n >> 24), byte(n >> 32), byte(n >> 40), byte(n >> 48), byte(n >> 56)}
+}
@@ -0,0 +1,85 @@
+package key
+
+import (
+	"errors"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/00-encoding/base58"
+)
+
+const (
+	wifMainnetPrefix  = 0x80
+	wifTestnetPrefix  = 0xEF
+	wifCompressedFlag = 0x01
+)
+
+// WIF is a private key decoded from Wallet Import Format
+type WIF struct {
+	PrivateKey *PrivateKey
+	// Compressed reports whether the key is used with the compressed public key
+	Compressed bool
+	IsTestnet  bool
+}
+
+// EncodeWIF encodes the private key in Wallet Import Format, e.g. for
+// Bitcoin Core importprivkey. Keys starting with 5 (mainnet) or 9 (testnet)
+// are uncompressed, keys starting with K, L or c are compressed.
+func EncodeWIF(privateKey *PrivateKey, compressed bool, isTestnet bool) string {
+	prefix := byte(wifMainnetPrefix)
+	if isTestnet {
+		prefix = wifTestnetPrefix
+	}
+
+	payload := append([]byte{prefix}, privateKey.Serialize()...)
+	if compressed {
+		payload = append(payload, wifCompressedFlag)
+	}
+	return base58.CheckEncode(payload)
+}
+
+// DecodeWIF decodes a Wallet Import Format private key and verifies its checksum
+func DecodeWIF(wif string) (*WIF, error) {
+	payload, err := base58.CheckDecode(wif)
+	if err != nil {
+		return nil, err
+	}
+
+	var compressed bool
+	switch len(payload) {
+	case 1 + privateKeySize:
+		compressed = false
+	case 1 + privateKeySize + 1:
+		if payload[len(payload)-1] != wifCompressedFlag {
+			return nil, errors.New("Invalid WIF compression flag: must be 0x01")
+		}
+		compressed = true
+	default:
+		return nil, errors.New("Invalid WIF length: must be 33 or 34 bytes")
+	}
+
+	var isTestnet bool
+	switch payload[0] {
+	case wifMainnetPrefix:
+		isTestnet = false
+	case wifTestnetPrefix:
+		isTestnet = true
+	default:
+		return nil, errors.New("Invalid WIF prefix: must be 0x80 or 0xEF")
+	}
+
+	keyBytes := payload[1 : 1+privateKeySize]
+	privateKey, err := PrivateKeyFromByte(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WIF{
+		PrivateKey: privateKey,
+		Compressed: compressed,
+		IsTestnet:  isTestnet,
+	}, nil
+}
+
+// String re-encodes the key in Wallet Import Format
+func (w *WIF) String() string {
+	return EncodeWIF(w.PrivateKey, w.Compressed, w.IsTestnet)
+}
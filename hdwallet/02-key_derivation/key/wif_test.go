@@ -0,0 +1,74 @@
+package key
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/00-encoding/base58"
+)
+
+func TestWIF(t *testing.T) {
+	privateKeyBytes, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	privateKey, _ := PrivateKeyFromByte(privateKeyBytes)
+
+	tests := []struct {
+		wif        string
+		compressed bool
+		isTestnet  bool
+	}{
+		{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", false, false},
+		{"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", true, false},
+	}
+	for _, test := range tests {
+		wif := EncodeWIF(privateKey, test.compressed, test.isTestnet)
+		if wif != test.wif {
+			t.Fatalf("got %s, want %s", wif, test.wif)
+		}
+
+		decoded, err := DecodeWIF(test.wif)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.PrivateKey.Hex() != privateKey.Hex() || decoded.Compressed != test.compressed || decoded.IsTestnet != test.isTestnet {
+			t.Fatalf("%s: got %s compressed=%v testnet=%v", test.wif, decoded.PrivateKey.Hex(), decoded.Compressed, decoded.IsTestnet)
+		}
+	}
+
+	// testnet keys start with 9 (uncompressed) or c (compressed)
+	for _, compressed := range []bool{false, true} {
+		wif := EncodeWIF(privateKey, compressed, true)
+		if (compressed && wif[0] != 'c') || (!compressed && wif[0] != '9') {
+			t.Fatalf("unexpected testnet WIF %s", wif)
+		}
+		decoded, err := DecodeWIF(wif)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.IsTestnet || decoded.Compressed != compressed || decoded.String() != wif {
+			t.Fatalf("%s: round trip failed", wif)
+		}
+	}
+}
+
+func TestDecodeWIFInvalid(t *testing.T) {
+	tests := []string{
+		// checksum mismatch
+		"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98618",
+		// invalid base58 character
+		"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP9861O",
+		// P2PKH address, wrong prefix and length
+		"1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S",
+		// compression flag 0x02
+		base58.CheckEncode(append(append([]byte{wifMainnetPrefix}, make([]byte, 31)...), 0x01, 0x02)),
+		// zero private key
+		base58.CheckEncode(append([]byte{wifMainnetPrefix}, make([]byte, 32)...)),
+		// private key equal to n
+		base58.CheckEncode(append([]byte{wifMainnetPrefix}, n.Bytes()...)),
+	}
+	for _, test := range tests {
+		_, err := DecodeWIF(test)
+		if err == nil {
+			t.Fatalf("%s: expected error", test)
+		}
+	}
+}
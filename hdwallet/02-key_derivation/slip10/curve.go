@@ -0,0 +1,98 @@
+package slip10
+
+import (
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// Curve abstracts the curve specific parts of SLIP-10 derivation
+type Curve interface {
+	// Name returns the SLIP-10 curve name
+	Name() string
+	// SeedKey returns the HMAC-SHA512 key used to generate the master node
+	SeedKey() []byte
+	// HardenedOnly reports whether only hardened children can be derived
+	HardenedOnly() bool
+	// PublicKey returns the 33-byte SLIP-10 public key of the private key
+	PublicKey(privateKey []byte) []byte
+	// ChildKey returns the child private key for IL, or false when IL must be
+	// discarded and derivation retried. parentKey is nil for the master node.
+	ChildKey(IL []byte, parentKey []byte) ([]byte, bool)
+}
+
+var (
+	// Secp256k1 derives the same keys as BIP-32
+	Secp256k1 Curve = &weierstrassCurve{"secp256k1", []byte("Bitcoin seed"), btcec.S256()}
+	// NIST256p1 is the NIST P-256 (secp256r1) curve
+	NIST256p1 Curve = &weierstrassCurve{"nist256p1", []byte("Nist256p1 seed"), elliptic.P256()}
+	// Ed25519 is used by Solana, Stellar, Aptos, Sui and NEAR and only supports hardened derivation
+	Ed25519 Curve = ed25519Curve{}
+)
+
+type weierstrassCurve struct {
+	name    string
+	seedKey []byte
+	curve   elliptic.Curve
+}
+
+func (c *weierstrassCurve) Name() string {
+	return c.name
+}
+
+func (c *weierstrassCurve) SeedKey() []byte {
+	return c.seedKey
+}
+
+func (c *weierstrassCurve) HardenedOnly() bool {
+	return false
+}
+
+func (c *weierstrassCurve) PublicKey(privateKey []byte) []byte {
+	x, y := c.curve.ScalarBaseMult(privateKey)
+	return elliptic.MarshalCompressed(c.curve, x, y)
+}
+
+// ChildKey returns parse256(IL) + kpar (mod n), rejecting IL >= n and a zero key
+func (c *weierstrassCurve) ChildKey(IL []byte, parentKey []byte) ([]byte, bool) {
+	order := c.curve.Params().N
+	key := new(big.Int).SetBytes(IL)
+	if key.Cmp(order) >= 0 {
+		return nil, false
+	}
+	if parentKey != nil {
+		key.Add(key, new(big.Int).SetBytes(parentKey))
+		key.Mod(key, order)
+	}
+	if key.Sign() == 0 {
+		return nil, false
+	}
+	return key.FillBytes(make([]byte, 32)), true
+}
+
+type ed25519Curve struct{}
+
+func (ed25519Curve) Name() string {
+	return "ed25519"
+}
+
+func (ed25519Curve) SeedKey() []byte {
+	return []byte("ed25519 seed")
+}
+
+func (ed25519Curve) HardenedOnly() bool {
+	return true
+}
+
+// PublicKey returns 0x00 followed by the 32-byte ed25519 public key
+func (ed25519Curve) PublicKey(privateKey []byte) []byte {
+	publicKey := ed25519.NewKeyFromSeed(privateKey).Public().(ed25519.PublicKey)
+	return append([]byte{0x00}, publicKey...)
+}
+
+// ChildKey uses IL as the child key, every value is valid
+func (ed25519Curve) ChildKey(IL []byte, parentKey []byte) ([]byte, bool) {
+	return append([]byte(nil), IL...), true
+}
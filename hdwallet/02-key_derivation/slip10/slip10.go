@@ -0,0 +1,150 @@
+package slip10
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+)
+
+// Node is a private key of a SLIP-10 hierarchy on any supported curve
+type Node struct {
+	curve             Curve
+	privKey           []byte
+	chainCode         []byte
+	depth             uint8
+	parentFingerprint []byte
+	childNumber       uint32
+}
+
+// NewMasterNode generates the master node of the curve from a seed of 16 to 64 bytes
+func NewMasterNode(seed []byte, curve Curve) (*Node, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, errors.New("Invalid seed length: must be between 16 and 64 bytes")
+	}
+
+	h := hmac.New(sha512.New, curve.SeedKey())
+	h.Write(seed)
+	I := h.Sum(nil)
+	for {
+		privKey, ok := curve.ChildKey(I[:32], nil)
+		if ok {
+			return &Node{
+				curve:     curve,
+				privKey:   privKey,
+				chainCode: I[32:],
+			}, nil
+		}
+		// an invalid master key is retried with I as the seed
+		h = hmac.New(sha512.New, curve.SeedKey())
+		h.Write(I)
+		I = h.Sum(nil)
+	}
+}
+
+// DerivePath derives the node at an absolute path from the master node
+// or at a relative path from any node
+func (n *Node) DerivePath(path string) (*Node, error) {
+	derivationPath, err := key.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if !derivationPath.Relative && n.depth != 0 {
+		return nil, errors.New("Invalid derivation path: absolute path must be derived from the master key")
+	}
+
+	node := n
+	for _, index := range derivationPath.Indexes {
+		node, err = node.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// Derive derives the child node at the index
+func (n *Node) Derive(index uint32) (*Node, error) {
+	hardened := index >= key.HardenedOffset
+	if !hardened && n.curve.HardenedOnly() {
+		return nil, errors.New("Invalid index value: " + n.curve.Name() + " only supports hardened derivation")
+	}
+
+	data := make([]byte, 37)
+	if hardened {
+		copy(data[1:], n.privKey)
+	} else {
+		copy(data, n.curve.PublicKey(n.privKey))
+	}
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	for {
+		h := hmac.New(sha512.New, n.chainCode)
+		h.Write(data)
+		I := h.Sum(nil)
+
+		privKey, ok := n.curve.ChildKey(I[:32], n.privKey)
+		if ok {
+			return &Node{
+				curve:             n.curve,
+				privKey:           privKey,
+				chainCode:         I[32:],
+				depth:             n.depth + 1,
+				parentFingerprint: n.Fingerprint(),
+				childNumber:       index,
+			}, nil
+		}
+		// an invalid child key is retried with 0x01 || IR || ser32(index)
+		data[0] = 0x01
+		copy(data[1:33], I[32:])
+	}
+}
+
+func (n *Node) Curve() Curve {
+	return n.curve
+}
+
+func (n *Node) PrivateKey() []byte {
+	return n.privKey
+}
+
+// PublicKey returns the 33-byte SLIP-10 public key, compressed for
+// secp256k1 and NIST P-256 and prefixed with 0x00 for ed25519
+func (n *Node) PublicKey() []byte {
+	return n.curve.PublicKey(n.privKey)
+}
+
+// Ed25519PrivateKey returns the key of an ed25519 node for signing
+func (n *Node) Ed25519PrivateKey() (ed25519.PrivateKey, error) {
+	if n.curve != Ed25519 {
+		return nil, errors.New("Invalid curve: " + n.curve.Name() + " node is not an ed25519 key")
+	}
+	return ed25519.NewKeyFromSeed(n.privKey), nil
+}
+
+func (n *Node) ChainCode() []byte {
+	return n.chainCode
+}
+
+func (n *Node) Depth() uint8 {
+	return n.depth
+}
+
+// Fingerprint returns the first 4 bytes of HASH160 of the public key
+func (n *Node) Fingerprint() []byte {
+	return key.Hash160(n.PublicKey())[:4]
+}
+
+func (n *Node) ParentFingerprint() []byte {
+	if n.parentFingerprint == nil {
+		return make([]byte, 4)
+	}
+	return n.parentFingerprint
+}
+
+func (n *Node) ChildNumber() uint32 {
+	return n.childNumber
+}
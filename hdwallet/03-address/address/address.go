@@ -1,59 +1,71 @@
 package address
 
 import (
-	"crypto/sha256"
 	"encoding/hex"
 	"errors"
-	"math/big"
 	"strings"
 
-	"golang.org/x/crypto/ripemd160"
 	"golang.org/x/crypto/sha3"
 	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/00-encoding/base58"
 )
 
-const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
-
 // ToP2PKHAddress converts uncompressed public key to p2pkh address
 func ToP2PKHAddress(publicKey *key.PublicKey, isTestnet bool) string {
-	sha256Hash := sha256.Sum256(publicKey.SerializeUnCompressed())
-
-	ripemd160Hasher := ripemd160.New()
-	ripemd160Hasher.Write(sha256Hash[:])
-	pubKeyHash := ripemd160Hasher.Sum(nil)
+	return p2pkhAddress(publicKey.SerializeUnCompressed(), isTestnet)
+}
 
-	var prefix byte
-	if isTestnet {
-		prefix = 0x6F
-	} else {
-		prefix = 0x00
-	}
-	prefixPayload := append([]byte{prefix}, pubKeyHash...)
+// ToCompressedP2PKHAddress converts compressed public key to p2pkh address
+func ToCompressedP2PKHAddress(publicKey *key.PublicKey, isTestnet bool) string {
+	return p2pkhAddress(publicKey.Serialize(), isTestnet)
+}
 
-	firstSHA := sha256.Sum256(prefixPayload)
-	secondSHA := sha256.Sum256(firstSHA[:])
-	checksum := secondSHA[:4]
+// ToP2WPKHAddress converts compressed public key to native segwit p2wpkh address
+// of the human-readable part, e.g. MainnetHRP for bc1q...
+func ToP2WPKHAddress(publicKey *key.PublicKey, hrp string) (string, error) {
+	return EncodeSegWitAddress(hrp, 0, key.Hash160(publicKey.Serialize()))
+}
 
-	fullPayload := append(prefixPayload, checksum...)
+// ToP2TRAddress converts public key to single-key taproot p2tr address (BIP-86),
+// committing to the x-only output key tweaked without script path
+func ToP2TRAddress(publicKey *key.PublicKey, hrp string) (string, error) {
+	outputKey, err := publicKey.TweakTaproot(nil)
+	if err != nil {
+		return "", err
+	}
+	return EncodeSegWitAddress(hrp, 1, outputKey.SerializeXOnly())
+}
 
-	return base58Encode(fullPayload)
+// ToP2SHAddress converts redeem script to p2sh address
+func ToP2SHAddress(redeemScript []byte, isTestnet bool) string {
+	if isTestnet {
+		return base58CheckAddress(0xC4, key.Hash160(redeemScript))
+	}
+	return base58CheckAddress(0x05, key.Hash160(redeemScript))
 }
 
-// base58Encode encodes string
-func base58Encode(input []byte) string {
-	var result []byte
-	num := new(big.Int).SetBytes(input)
+// ToP2SHP2WPKHAddress converts compressed public key to p2wpkh nested in p2sh address (BIP-49)
+func ToP2SHP2WPKHAddress(publicKey *key.PublicKey, isTestnet bool) string {
+	return ToP2SHAddress(P2WPKHRedeemScript(publicKey), isTestnet)
+}
 
-	base := big.NewInt(58)
-	zero := big.NewInt(0)
+// P2WPKHRedeemScript returns the redeem script OP_0 <hash160 of compressed public key>
+// that a p2sh-p2wpkh address commits to
+func P2WPKHRedeemScript(publicKey *key.PublicKey) []byte {
+	return append([]byte{0x00, 0x14}, key.Hash160(publicKey.Serialize())...)
+}
 
-	for num.Cmp(zero) > 0 {
-		mod := new(big.Int)
-		num.DivMod(num, base, mod)
-		result = append([]byte{base58Alphabet[mod.Int64()]}, result...)
+// p2pkhAddress encodes hash160 of the serialized public key with base58check
+func p2pkhAddress(serializedPublicKey []byte, isTestnet bool) string {
+	if isTestnet {
+		return base58CheckAddress(0x6F, key.Hash160(serializedPublicKey))
 	}
+	return base58CheckAddress(0x00, key.Hash160(serializedPublicKey))
+}
 
-	return string(result)
+// base58CheckAddress prefixes the hash with the version byte and encodes it with base58check
+func base58CheckAddress(version byte, hash []byte) string {
+	return base58.CheckEncode(append([]byte{version}, hash...))
 }
 
 // ToEIP55Address converts uncompressed public key to eip55 address
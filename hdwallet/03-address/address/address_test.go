@@ -2,10 +2,12 @@ package address
 
 import (
 	"encoding/hex"
+	"errors"
 	"testing"
 	"log"
 
 	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/00-encoding/base58"
 )
 
 var (
@@ -24,3 +26,134 @@ func TestAddress(t *testing.T) {
 	ethAddress, _ := ToEIP55Address(ethereumPublicKey)
 	log.Println(ethAddress)
 }
+
+func TestP2PKHAddress(t *testing.T) {
+	// public key of private key 1
+	publicKeyBytes, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	publicKey, _ := key.PublicKeyFromByte(publicKeyBytes)
+
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{ToP2PKHAddress(publicKey, false), "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm"},
+		{ToCompressedP2PKHAddress(publicKey, false), "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{ToCompressedP2PKHAddress(publicKey, true), "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"},
+	}
+	for _, test := range tests {
+		if test.address != test.want {
+			t.Fatalf("got %s, want %s", test.address, test.want)
+		}
+	}
+}
+
+func TestP2SHAddress(t *testing.T) {
+	// BIP-49 test vector, m/49'/1'/0'/0/0 of the abandon ... about mnemonic
+	publicKeyBytes, _ := hex.DecodeString("03a1af804ac108a8a51782198c2d034b28bf90c8803f5a53f76276fa69a4eae77f")
+	publicKey, _ := key.PublicKeyFromByte(publicKeyBytes)
+
+	redeemScript := P2WPKHRedeemScript(publicKey)
+	if hex.EncodeToString(redeemScript) != "001438971f73930f6c141d977ac4fd4a727c854935b3" {
+		t.Fatalf("got redeem script %x", redeemScript)
+	}
+	if hex.EncodeToString(key.Hash160(redeemScript)) != "336caa13e08b96080a32b5d818d59b4ab3b36742" {
+		t.Fatalf("got script hash %x", key.Hash160(redeemScript))
+	}
+
+	// OP_TRUE
+	anyoneCanSpend := []byte{0x51}
+
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{ToP2SHP2WPKHAddress(publicKey, true), "2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2"},
+		{ToP2SHAddress(redeemScript, true), "2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2"},
+		{ToP2SHAddress(anyoneCanSpend, false), "3MaB7QVq3k4pQx3BhsvEADgzQonLSBwMdj"},
+	}
+	for _, test := range tests {
+		if test.address != test.want {
+			t.Fatalf("got %s, want %s", test.address, test.want)
+		}
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address     string
+		addressType AddressType
+		network     Network
+		payload     string
+	}{
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", P2PKH, BitcoinMainnet, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", P2PKH, BitcoinTestnet, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"3MaB7QVq3k4pQx3BhsvEADgzQonLSBwMdj", P2SH, BitcoinMainnet, "da1745e9b549bd0bfa1a569971c77eba30cd5a4b"},
+		{"2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2", P2SH, BitcoinTestnet, "336caa13e08b96080a32b5d818d59b4ab3b36742"},
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", P2WPKH, BitcoinMainnet, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080", P2WPKH, BitcoinRegtest, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", P2WSH, BitcoinTestnet, "1863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", P2TR, BitcoinMainnet, "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"},
+		// EIP-55 test vectors
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", EIP55, Ethereum, "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+		{"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", EIP55, Ethereum, "fb6916095ca1df60bb79ce92ce3ea74c37c5d359"},
+		{"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB", EIP55, Ethereum, "dbf03b407c01e7cd3cbea99509d93f8dddc8c6fb"},
+		{"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb", EIP55, Ethereum, "d1220a0cf47c7b9be7a2e6ba89f429762e7b9adb"},
+		// single-case addresses carry no checksum
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", EIP55, Ethereum, "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+		{"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", EIP55, Ethereum, "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+	}
+	for _, test := range tests {
+		parsed, err := ParseAddress(test.address)
+		if err != nil {
+			t.Fatalf("%s: %v", test.address, err)
+		}
+		if parsed.Type != test.addressType || parsed.Network != test.network || hex.EncodeToString(parsed.Payload) != test.payload {
+			t.Fatalf("%s: got %v %v %x, want %v %v %s", test.address, parsed.Type, parsed.Network, parsed.Payload, test.addressType, test.network, test.payload)
+		}
+	}
+
+	invalid := []struct {
+		address string
+		err     error
+	}{
+		{"", nil},
+		// base58 checksum mismatch
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMJ", ErrInvalidChecksum},
+		// invalid base58 character
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAM0", nil},
+		// WIF private key is not an address
+		{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", nil},
+		// segwit checksum mismatch
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", ErrInvalidChecksum},
+		// witness version 1 with Bech32 checksum
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", ErrInvalidChecksum},
+		// unsupported witness version
+		{"BC1SW50QGDZ25J", nil},
+		// EIP-55 checksum mismatch
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", ErrInvalidEIP55Checksum},
+		// Ethereum address length and characters
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", nil},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", nil},
+	}
+	for _, test := range invalid {
+		_, err := ParseAddress(test.address)
+		if err == nil {
+			t.Fatalf("%q: expected error", test.address)
+		}
+		if test.err != nil && !errors.Is(err, test.err) {
+			t.Fatalf("%q: got %v, want %v", test.address, err, test.err)
+		}
+	}
+
+	// addresses share the checksum error of WIF and extended keys
+	_, err := ParseAddress("1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMJ")
+	if !errors.Is(err, base58.ErrInvalidChecksum) {
+		t.Fatalf("got %v, want %v", err, base58.ErrInvalidChecksum)
+	}
+
+	// truncated input is reported by length, not as a checksum mismatch
+	_, err = ParseAddress("2g")
+	if err == nil || err == ErrInvalidChecksum {
+		t.Fatalf("truncated address: got %v, want length error", err)
+	}
+}
@@ -0,0 +1,278 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Human-readable parts of segwit addresses
+const (
+	MainnetHRP = "bc"
+	TestnetHRP = "tb"
+	RegtestHRP = "bcrt"
+)
+
+const (
+	bech32Charset        = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32ChecksumLength = 6
+	bech32MaxLength      = 90
+
+	// checksum constants of Bech32 (BIP-173) and Bech32m (BIP-350)
+	bech32Constant  = 1
+	bech32mConstant = 0x2bc830a3
+)
+
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// Bech32Encode encodes the human-readable part and 5-bit data values as defined in BIP-173
+func Bech32Encode(hrp string, data []byte) (string, error) {
+	return bech32Encode(hrp, data, bech32Constant)
+}
+
+// Bech32mEncode encodes the human-readable part and 5-bit data values as defined in BIP-350
+func Bech32mEncode(hrp string, data []byte) (string, error) {
+	return bech32Encode(hrp, data, bech32mConstant)
+}
+
+// Bech32Decode verifies the Bech32 checksum and returns the lowercase
+// human-readable part and the 5-bit data values without checksum
+func Bech32Decode(encoded string) (string, []byte, error) {
+	return bech32DecodeAs(encoded, bech32Constant)
+}
+
+// Bech32mDecode verifies the Bech32m checksum and returns the lowercase
+// human-readable part and the 5-bit data values without checksum
+func Bech32mDecode(encoded string) (string, []byte, error) {
+	return bech32DecodeAs(encoded, bech32mConstant)
+}
+
+func bech32Encode(hrp string, data []byte, constant uint32) (string, error) {
+	if err := validateHRP(hrp); err != nil {
+		return "", err
+	}
+	if hrp != strings.ToLower(hrp) && hrp != strings.ToUpper(hrp) {
+		return "", errors.New("Invalid bech32 human-readable part: mixed case")
+	}
+	hrp = strings.ToLower(hrp)
+	if len(hrp)+1+len(data)+bech32ChecksumLength > bech32MaxLength {
+		return "", errors.New("Invalid bech32 length: must be at most 90 characters")
+	}
+
+	var result strings.Builder
+	result.WriteString(hrp)
+	result.WriteByte('1')
+	for _, value := range data {
+		if value >= 32 {
+			return "", errors.New("Invalid bech32 data: values must be 5 bits")
+		}
+		result.WriteByte(bech32Charset[value])
+	}
+	for _, value := range bech32Checksum(hrp, data, constant) {
+		result.WriteByte(bech32Charset[value])
+	}
+	return result.String(), nil
+}
+
+func bech32DecodeAs(encoded string, constant uint32) (string, []byte, error) {
+	hrp, data, decodedConstant, err := bech32Decode(encoded)
+	if err != nil {
+		return "", nil, err
+	}
+	if decodedConstant != constant {
+		return "", nil, fmt.Errorf("%w: %s instead of %s", ErrInvalidChecksum, bech32Variant(decodedConstant), bech32Variant(constant))
+	}
+	return hrp, data, nil
+}
+
+// bech32Decode validates the string and returns the lowercase human-readable part,
+// the data values without checksum and the checksum constant, Bech32 or Bech32m
+func bech32Decode(encoded string) (string, []byte, uint32, error) {
+	if len(encoded) > bech32MaxLength {
+		return "", nil, 0, errors.New("Invalid bech32 length: must be at most 90 characters")
+	}
+	if encoded != strings.ToLower(encoded) && encoded != strings.ToUpper(encoded) {
+		return "", nil, 0, errors.New("Invalid bech32 string: mixed case")
+	}
+	encoded = strings.ToLower(encoded)
+
+	separator := strings.LastIndexByte(encoded, '1')
+	if separator < 0 {
+		return "", nil, 0, errors.New("Invalid bech32 string: missing separator '1'")
+	}
+	hrp := encoded[:separator]
+	if err := validateHRP(hrp); err != nil {
+		return "", nil, 0, err
+	}
+	if len(encoded)-separator-1 < bech32ChecksumLength {
+		return "", nil, 0, errors.New("Invalid bech32 checksum: too short")
+	}
+
+	data := make([]byte, 0, len(encoded)-separator-1)
+	for i := separator + 1; i < len(encoded); i++ {
+		value := strings.IndexByte(bech32Charset, encoded[i])
+		if value < 0 {
+			return "", nil, 0, errors.New("Invalid bech32 character: " + string(encoded[i]))
+		}
+		data = append(data, byte(value))
+	}
+
+	constant := bech32Polymod(append(bech32HRPExpand(hrp), data...))
+	if constant != bech32Constant && constant != bech32mConstant {
+		return "", nil, 0, ErrInvalidChecksum
+	}
+	return hrp, data[:len(data)-bech32ChecksumLength], constant, nil
+}
+
+func bech32Variant(constant uint32) string {
+	if constant == bech32mConstant {
+		return "bech32m"
+	}
+	return "bech32"
+}
+
+// EncodeSegWitAddress encodes a witness program as a segwit address,
+// with Bech32 for version 0 and Bech32m for versions 1 to 16
+func EncodeSegWitAddress(hrp string, version byte, program []byte) (string, error) {
+	if err := validateWitnessProgram(version, program); err != nil {
+		return "", err
+	}
+	data, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32Encode(hrp, append([]byte{version}, data...), witnessVersionConstant(version))
+}
+
+// DecodeSegWitAddress decodes a segwit address of the human-readable part and
+// returns the witness version and program
+func DecodeSegWitAddress(hrp string, address string) (byte, []byte, error) {
+	decodedHRP, data, constant, err := bech32Decode(address)
+	if err != nil {
+		return 0, nil, err
+	}
+	if decodedHRP != hrp {
+		return 0, nil, errors.New("Invalid segwit address: human-readable part " + decodedHRP + ", expected " + hrp)
+	}
+	if len(data) == 0 {
+		return 0, nil, errors.New("Invalid segwit address: empty data")
+	}
+
+	version := data[0]
+	if version <= 16 && constant != witnessVersionConstant(version) {
+		return 0, nil, fmt.Errorf("%w: witness version %d must use %s", ErrInvalidChecksum, version, bech32Variant(witnessVersionConstant(version)))
+	}
+
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := validateWitnessProgram(version, program); err != nil {
+		return 0, nil, err
+	}
+	return version, program, nil
+}
+
+// witnessVersionConstant returns the checksum constant of the witness version (BIP-350)
+func witnessVersionConstant(version byte) uint32 {
+	if version == 0 {
+		return bech32Constant
+	}
+	return bech32mConstant
+}
+
+func validateHRP(hrp string) error {
+	if len(hrp) < 1 || len(hrp) > 83 {
+		return errors.New("Invalid bech32 human-readable part: must be 1 to 83 characters")
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return errors.New("Invalid bech32 human-readable part: characters must be in range 33-126")
+		}
+	}
+	return nil
+}
+
+func validateWitnessProgram(version byte, program []byte) error {
+	if version > 16 {
+		return errors.New("Invalid witness version: must be between 0 and 16")
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return errors.New("Invalid witness program length: must be 2 to 40 bytes")
+	}
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return errors.New("Invalid witness program length: version 0 requires 20 or 32 bytes")
+	}
+	return nil
+}
+
+// bech32Checksum returns the 6 checksum values of the human-readable part and data
+func bech32Checksum(hrp string, data []byte, constant uint32) []byte {
+	values := append(bech32HRPExpand(hrp), data...)
+	values = append(values, make([]byte, bech32ChecksumLength)...)
+	polymod := bech32Polymod(values) ^ constant
+
+	checksum := make([]byte, bech32ChecksumLength)
+	for i := range checksum {
+		checksum[i] = byte(polymod>>(5*(5-i))) & 31
+	}
+	return checksum
+}
+
+// bech32HRPExpand returns the high bits, a zero and the low bits of each character
+func bech32HRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+func bech32Polymod(values []byte) uint32 {
+	checksum := uint32(1)
+	for _, value := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(value)
+		for i, generator := range bech32Generator {
+			if (top>>i)&1 == 1 {
+				checksum ^= generator
+			}
+		}
+	}
+	return checksum
+}
+
+// convertBits regroups data of fromBits-bit values into toBits-bit values.
+// Without padding, leftover bits must be fewer than fromBits and all zero.
+func convertBits(data []byte, fromBits uint, toBits uint, pad bool) ([]byte, error) {
+	var accumulator uint32
+	var bits uint
+	maxValue := uint32(1)<<toBits - 1
+	result := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, errors.New("Invalid data value: exceeds bit width")
+		}
+		accumulator = accumulator<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(accumulator>>bits&maxValue))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(accumulator<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits {
+		return nil, errors.New("Invalid padding: more than 4 bits of padding")
+	} else if accumulator<<(toBits-bits)&maxValue != 0 {
+		return nil, errors.New("Invalid padding: non-zero padding bits")
+	}
+	return result, nil
+}
@@ -0,0 +1,320 @@
+package address
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/01-mnemonic/mnemonic"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+)
+
+func TestBech32Checksum(t *testing.T) {
+	valid := []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"11" + strings.Repeat("q", 82) + "c8247j",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+		"?1ezyfcl",
+	}
+	for _, test := range valid {
+		hrp, data, err := Bech32Decode(test)
+		if err != nil {
+			t.Fatalf("%s: %v", test, err)
+		}
+		encoded, err := Bech32Encode(hrp, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encoded != strings.ToLower(test) {
+			t.Fatalf("got %s, want %s", encoded, strings.ToLower(test))
+		}
+	}
+
+	invalid := []string{
+		// human-readable part character out of range
+		"\x201nwldj5",
+		"\x7f1axkwrx",
+		// overall max length exceeded
+		"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx",
+		// no separator
+		"pzry9x0s0muk",
+		// empty human-readable part
+		"1pzry9x0s0muk",
+		"10a06t8",
+		"1qzzfhee",
+		// invalid data character
+		"x1b4n0q5v",
+		// too short checksum
+		"li1dgmt3",
+		// checksum calculated with uppercase human-readable part
+		"A1G7SGD8",
+	}
+	for _, test := range invalid {
+		_, _, err := Bech32Decode(test)
+		if err == nil {
+			t.Fatalf("%q: expected error", test)
+		}
+	}
+}
+
+func TestBech32mChecksum(t *testing.T) {
+	valid := []string{
+		"A1LQFN3A",
+		"a1lqfn3a",
+		"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6",
+		"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+		"11" + strings.Repeat("l", 83) + "udsr8",
+		"split1checkupstagehandshakeupstreamerranterredcaperredlc445v",
+		"?1v759aa",
+	}
+	for _, test := range valid {
+		hrp, data, err := Bech32mDecode(test)
+		if err != nil {
+			t.Fatalf("%s: %v", test, err)
+		}
+		encoded, err := Bech32mEncode(hrp, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encoded != strings.ToLower(test) {
+			t.Fatalf("got %s, want %s", encoded, strings.ToLower(test))
+		}
+
+		// a Bech32m string is not a valid Bech32 string
+		_, _, err = Bech32Decode(test)
+		if !errors.Is(err, ErrInvalidChecksum) {
+			t.Fatalf("%s: got %v, want %v", test, err, ErrInvalidChecksum)
+		}
+	}
+
+	invalid := []string{
+		// human-readable part character out of range
+		"\x201xj0phk",
+		"\x7f1g6xzxy",
+		// overall max length exceeded
+		"an84characterslonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11d6pts4",
+		// no separator
+		"qyrz8wqd2c9m",
+		// empty human-readable part
+		"1qyrz8wqd2c9m",
+		"16plkw9",
+		"1p2gdwpf",
+		// invalid data character
+		"y1b0jsk6g",
+		"lt1igcx5c0",
+		// too short checksum
+		"in1muywd",
+		// invalid character in checksum
+		"mm1crxm3i",
+		"au1s5cgom",
+		// checksum calculated with uppercase human-readable part
+		"M1VUXWEZ",
+	}
+	for _, test := range invalid {
+		_, _, err := Bech32mDecode(test)
+		if err == nil {
+			t.Fatalf("%q: expected error", test)
+		}
+	}
+}
+
+func TestSegWitAddress(t *testing.T) {
+	valid := []struct {
+		address      string
+		hrp          string
+		scriptPubKey string
+	}{
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", MainnetHRP, "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", TestnetHRP, "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{"tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy", TestnetHRP, "0020000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+		{"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y", MainnetHRP, "5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"BC1SW50QGDZ25J", MainnetHRP, "6002751e"},
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", MainnetHRP, "5210751e76e8199196d454941c45d1b3a323"},
+		{"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c", TestnetHRP, "5120000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", MainnetHRP, "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+	}
+	for _, test := range valid {
+		version, program, err := DecodeSegWitAddress(test.hrp, test.address)
+		if err != nil {
+			t.Fatalf("%s: %v", test.address, err)
+		}
+		// OP_0 or OP_1 to OP_16 followed by the program push
+		opcode := version
+		if version > 0 {
+			opcode += 0x50
+		}
+		scriptPubKey := append([]byte{opcode, byte(len(program))}, program...)
+		if hex.EncodeToString(scriptPubKey) != test.scriptPubKey {
+			t.Fatalf("%s: got %x, want %s", test.address, scriptPubKey, test.scriptPubKey)
+		}
+
+		encoded, err := EncodeSegWitAddress(test.hrp, version, program)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encoded != strings.ToLower(test.address) {
+			t.Fatalf("got %s, want %s", encoded, strings.ToLower(test.address))
+		}
+	}
+
+	invalid := []struct {
+		address string
+		hrp     string
+	}{
+		// invalid human-readable part
+		{"tc1qw508d6qejxtdg4y5r3zarvary0c5xw7kg3g4ty", MainnetHRP},
+		// human-readable part of another network
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", MainnetHRP},
+		// invalid checksum
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", MainnetHRP},
+		// invalid program length for witness version 0
+		{"BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P", MainnetHRP},
+		// mixed case
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sL5k7", TestnetHRP},
+		// zero padding of more than 4 bits
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvqyzf3du", MainnetHRP},
+		// non-zero padding in 8-to-5 conversion
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3pjxtptv", TestnetHRP},
+		// empty data section
+		{"bc1gmk9yu", MainnetHRP},
+		// invalid human-readable part with witness version 1
+		{"tc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut", MainnetHRP},
+		// witness version 1 and above with Bech32 checksum
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", MainnetHRP},
+		{"tb1z0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqglt7rf", TestnetHRP},
+		{"BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL", MainnetHRP},
+		// witness version 0 with Bech32m checksum
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", MainnetHRP},
+		{"tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47", TestnetHRP},
+		// invalid character in checksum
+		{"bc1p38j9r5y49hruaue7wxjce0updqjuyyx0kh56v8s25huc6995vvpql3jow4", MainnetHRP},
+		// invalid witness version
+		{"BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R", MainnetHRP},
+		// invalid program length
+		{"bc1pw5dgrnzv", MainnetHRP},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v8n0nx0muaewav253zgeav", MainnetHRP},
+		// zero padding of more than 4 bits with witness version 1
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v07qwwzcrf", MainnetHRP},
+		// non-zero padding in 8-to-5 conversion with witness version 1
+		{"tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vpggkg4j", TestnetHRP},
+	}
+	for _, test := range invalid {
+		_, _, err := DecodeSegWitAddress(test.hrp, test.address)
+		if err == nil {
+			t.Fatalf("%s: expected error", test.address)
+		}
+	}
+
+	checksumErrors := []struct {
+		address string
+		hrp     string
+	}{
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", MainnetHRP},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", MainnetHRP},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", MainnetHRP},
+	}
+	for _, test := range checksumErrors {
+		_, _, err := DecodeSegWitAddress(test.hrp, test.address)
+		if !errors.Is(err, ErrInvalidChecksum) {
+			t.Fatalf("%s: got %v, want %v", test.address, err, ErrInvalidChecksum)
+		}
+	}
+}
+
+func TestP2WPKHAddress(t *testing.T) {
+	// public key of private key 1
+	publicKeyBytes, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	publicKey, _ := key.PublicKeyFromByte(publicKeyBytes)
+
+	tests := []struct {
+		hrp  string
+		want string
+	}{
+		{MainnetHRP, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{TestnetHRP, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+		{RegtestHRP, "bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080"},
+	}
+	for _, test := range tests {
+		address, err := ToP2WPKHAddress(publicKey, test.hrp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if address != test.want {
+			t.Fatalf("got %s, want %s", address, test.want)
+		}
+
+		_, program, err := DecodeSegWitAddress(test.hrp, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(program, key.Hash160(publicKey.Serialize())) {
+			t.Fatalf("%s: got program %x", address, program)
+		}
+	}
+}
+
+func TestP2TRAddress(t *testing.T) {
+	// BIP-86 test vectors
+	seed := mnemonic.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	master, err := key.NewMasterFromSeed(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path        string
+		internalKey string
+		outputKey   string
+		want        string
+	}{
+		{
+			"m/86'/0'/0'/0/0",
+			"cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115",
+			"a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+			"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr",
+		},
+		{
+			"m/86'/0'/0'/0/1",
+			"83dfe85a3151d2517290da461fe2815591ef69f2b18a2ce63f01697a8b313145",
+			"a82f29944d65b86ae6b5e5cc75e294ead6c59391a1edc5e016e3498c67fc7bbb",
+			"bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh",
+		},
+		{
+			"m/86'/0'/0'/1/0",
+			"399f1b2f4393f29a18c937859c5dd8a77350103157eb880f02e8c08214277cef",
+			"882d74e5d0572d5a816cef0041a96b6c1de832f6f9676d9605c44d5e9a97d3dc",
+			"bc1p3qkhfews2uk44qtvauqyr2ttdsw7svhkl9nkm9s9c3x4ax5h60wqwruhk7",
+		},
+	}
+	for _, test := range tests {
+		derived, err := master.DerivePath(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKey := derived.PublicKey()
+		if hex.EncodeToString(publicKey.SerializeXOnly()) != test.internalKey {
+			t.Fatalf("%s: got internal key %x, want %s", test.path, publicKey.SerializeXOnly(), test.internalKey)
+		}
+
+		address, err := ToP2TRAddress(publicKey, MainnetHRP)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if address != test.want {
+			t.Fatalf("%s: got %s, want %s", test.path, address, test.want)
+		}
+
+		version, program, err := DecodeSegWitAddress(MainnetHRP, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version != 1 || hex.EncodeToString(program) != test.outputKey {
+			t.Fatalf("%s: got version %d output key %x, want 1 %s", test.path, version, program, test.outputKey)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/00-encoding/base58"
+)
+
+// AddressType identifies what an address pays to
+type AddressType int
+
+const (
+	P2PKH AddressType = iota + 1
+	P2SH
+	P2WPKH
+	P2WSH
+	P2TR
+	EIP55
+)
+
+func (t AddressType) String() string {
+	switch t {
+	case P2PKH:
+		return "P2PKH"
+	case P2SH:
+		return "P2SH"
+	case P2WPKH:
+		return "P2WPKH"
+	case P2WSH:
+		return "P2WSH"
+	case P2TR:
+		return "P2TR"
+	case EIP55:
+		return "EIP-55"
+	}
+	return "unknown"
+}
+
+// Network identifies the chain an address belongs to
+type Network string
+
+const (
+	BitcoinMainnet Network = "bitcoin"
+	// BitcoinTestnet also covers regtest for base58 addresses, which share the version bytes
+	BitcoinTestnet Network = "bitcoin-testnet"
+	BitcoinRegtest Network = "bitcoin-regtest"
+	Ethereum       Network = "ethereum"
+)
+
+var (
+	ErrInvalidChecksum      = base58.ErrInvalidChecksum
+	ErrInvalidEIP55Checksum = errors.New("Invalid EIP-55 checksum: mixed-case address does not match its checksum")
+)
+
+// Address is a decoded address
+type Address struct {
+	Type    AddressType
+	Network Network
+	// Payload is the hash160 of P2PKH and P2SH, the witness program of
+	// P2WPKH, P2WSH and P2TR, and the 20-byte account of EIP-55 addresses
+	Payload []byte
+}
+
+// ParseAddress identifies the chain and type of the address and verifies its checksum
+func ParseAddress(address string) (*Address, error) {
+	if address == "" {
+		return nil, errors.New("Invalid address: empty")
+	}
+
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return parseEthereumAddress(address)
+	}
+
+	lower := strings.ToLower(address)
+	for _, hrp := range []string{MainnetHRP, TestnetHRP, RegtestHRP} {
+		if strings.HasPrefix(lower, hrp+"1") {
+			return parseSegWitAddress(hrp, address)
+		}
+	}
+
+	return parseBase58Address(address)
+}
+
+func parseEthereumAddress(address string) (*Address, error) {
+	if len(address) != 42 {
+		return nil, fmt.Errorf("Invalid Ethereum address length: must be 42 characters, got %d", len(address))
+	}
+	account, err := hex.DecodeString(address[2:])
+	if err != nil {
+		return nil, errors.New("Invalid Ethereum address: must be 40 hexadecimal characters after 0x")
+	}
+
+	// all lowercase or all uppercase addresses carry no checksum
+	hexAddress := address[2:]
+	if hexAddress != strings.ToLower(hexAddress) && hexAddress != strings.ToUpper(hexAddress) {
+		checksummed, err := eip55Checksum("0x" + hexAddress)
+		if err != nil {
+			return nil, err
+		}
+		if checksummed[2:] != hexAddress {
+			return nil, ErrInvalidEIP55Checksum
+		}
+	}
+
+	return &Address{Type: EIP55, Network: Ethereum, Payload: account}, nil
+}
+
+func parseSegWitAddress(hrp string, address string) (*Address, error) {
+	version, program, err := DecodeSegWitAddress(hrp, address)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &Address{Payload: program}
+	switch hrp {
+	case MainnetHRP:
+		parsed.Network = BitcoinMainnet
+	case TestnetHRP:
+		parsed.Network = BitcoinTestnet
+	case RegtestHRP:
+		parsed.Network = BitcoinRegtest
+	}
+
+	switch {
+	case version == 0 && len(program) == 20:
+		parsed.Type = P2WPKH
+	case version == 0 && len(program) == 32:
+		parsed.Type = P2WSH
+	case version == 1 && len(program) == 32:
+		parsed.Type = P2TR
+	default:
+		return nil, fmt.Errorf("Unsupported segwit address: witness version %d with %d-byte program", version, len(program))
+	}
+	return parsed, nil
+}
+
+func parseBase58Address(address string) (*Address, error) {
+	decoded, err := base58.Decode(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) != 25 {
+		return nil, fmt.Errorf("Invalid base58 address length: must decode to 25 bytes, got %d", len(decoded))
+	}
+
+	payload, err := base58.VerifyChecksum(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &Address{Payload: payload[1:]}
+	switch payload[0] {
+	case 0x00:
+		parsed.Type, parsed.Network = P2PKH, BitcoinMainnet
+	case 0x6F:
+		parsed.Type, parsed.Network = P2PKH, BitcoinTestnet
+	case 0x05:
+		parsed.Type, parsed.Network = P2SH, BitcoinMainnet
+	case 0xC4:
+		parsed.Type, parsed.Network = P2SH, BitcoinTestnet
+	default:
+		return nil, fmt.Errorf("Unknown address version byte 0x%02x", payload[0])
+	}
+	return parsed, nil
+}
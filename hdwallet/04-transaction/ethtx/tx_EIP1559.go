@@ -2,22 +2,25 @@ package ethtx
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/05-signature/signer"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 // EIP1559Transaction structure
 type EIP1559Transaction struct {
 	client       *ethclient.Client
-	privateKey   *ecdsa.PrivateKey
+	signer       signer.Signer
+	path         string
 	to           common.Address
 	value        *big.Int
 	data         []byte
@@ -59,7 +62,7 @@ func (tx *EIP1559Transaction) Hash() common.Hash {
 	return tx.txHash
 }
 
-// NewEIP1559Transaction creates a new EIP-1559 transaction
+// NewEIP1559Transaction creates a new EIP-1559 transaction signed by the hex private key
 func NewEIP1559Transaction(
 	client *ethclient.Client,
 	privateKeyHex string,
@@ -69,23 +72,33 @@ func NewEIP1559Transaction(
 	gasLimit uint64,
 ) (*EIP1559Transaction, error) {
 	// Private key
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	privateKeySigner, err := newPrivateKeySigner(privateKeyHex)
 	if err != nil {
-		return nil, fmt.Errorf("private key parsing error: %w", err)
+		return nil, err
 	}
 
+	return NewEIP1559TransactionWithSigner(client, privateKeySigner, signer.PrivateKeyPath, toAddress, value, data, gasLimit)
+}
+
+// NewEIP1559TransactionWithSigner creates a new EIP-1559 transaction signed by the key at path of the signer
+func NewEIP1559TransactionWithSigner(
+	client *ethclient.Client,
+	s signer.Signer,
+	path string,
+	toAddress string,
+	value *big.Int,
+	data []byte,
+	gasLimit uint64,
+) (*EIP1559Transaction, error) {
 	// ToAddress
 	to := common.HexToAddress(toAddress)
 
 	// Nonce
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, fmt.Errorf("failed to convert to ECDSA public key")
+	fromAddress, err := s.Address(context.Background(), path)
+	if err != nil {
+		return nil, fmt.Errorf("error getting signer address: %w", err)
 	}
-
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-	nonce, err := client.PendingNonceAt(context.Background(), fromAddress)
+	nonce, err := client.PendingNonceAt(context.Background(), common.HexToAddress(fromAddress))
 	if err != nil {
 		return nil, fmt.Errorf("error getting nonce: %w", err)
 	}
@@ -115,7 +128,8 @@ func NewEIP1559Transaction(
 
 	return &EIP1559Transaction{
 		client:       client,
-		privateKey:   privateKey,
+		signer:       s,
+		path:         path,
 		to:           to,
 		value:        value,
 		data:         data,
@@ -127,9 +141,25 @@ func NewEIP1559Transaction(
 	}, nil
 }
 
-// Send broadcasts the transaction
-func (tx *EIP1559Transaction) Send(ctx context.Context) (string, error) {
-	// Create EIP-1559 transaction
+// newPrivateKeySigner parses the hex private key into a signer holding it at signer.PrivateKeyPath
+func newPrivateKeySigner(privateKeyHex string) (*signer.PrivateKeySigner, error) {
+	privateKeyBytes, err := hex.DecodeString(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("private key parsing error: %w", err)
+	}
+	privateKey, err := key.PrivateKeyFromByte(privateKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("private key parsing error: %w", err)
+	}
+	return signer.NewPrivateKeySigner(privateKey)
+}
+
+// Sign signs the transaction with the latest signer of the chain
+func (tx *EIP1559Transaction) Sign(ctx context.Context) (*types.Transaction, error) {
+	if tx.signer == nil {
+		return nil, fmt.Errorf("Missing signer: transaction must be created with a signer")
+	}
+
 	rawTx := types.NewTx(&types.DynamicFeeTx{
 		ChainID:   tx.chainID,
 		Nonce:     tx.nonce,
@@ -140,11 +170,19 @@ func (tx *EIP1559Transaction) Send(ctx context.Context) (string, error) {
 		Value:     tx.value,
 		Data:      tx.data,
 	})
+	signedTx, err := tx.signer.SignTx(ctx, tx.path, rawTx, tx.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("transaction signing error: %w", err)
+	}
+	return signedTx, nil
+}
 
+// Send broadcasts the transaction
+func (tx *EIP1559Transaction) Send(ctx context.Context) (string, error) {
 	// Sign transaction
-	signedTx, err := types.SignTx(rawTx, types.LatestSignerForChainID(tx.chainID), tx.privateKey)
+	signedTx, err := tx.Sign(ctx)
 	if err != nil {
-		return "", fmt.Errorf("transaction signing error: %w", err)
+		return "", err
 	}
 
 	// Send transaction
@@ -2,15 +2,14 @@ package ethtx
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"time"
 
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/05-signature/signer"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -18,7 +17,8 @@ import (
 // LegacyTransaction structure
 type LegacyTransaction struct {
 	client       *ethclient.Client
-	privateKey   *ecdsa.PrivateKey
+	signer       signer.Signer
+	path         string
 	to           common.Address
 	value        *big.Int
 	data         []byte
@@ -55,7 +55,7 @@ func (tx *LegacyTransaction) Hash() common.Hash {
 	return tx.txHash
 }
 
-// NewLegacyTransaction creates a new Legacy transaction 
+// NewLegacyTransaction creates a new Legacy transaction signed by the hex private key
 func NewLegacyTransaction(
 	client *ethclient.Client,
 	privateKeyHex string,
@@ -65,23 +65,30 @@ func NewLegacyTransaction(
 	gasLimit uint64,
 ) (*LegacyTransaction, error) {
 	// Private key
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	privateKeySigner, err := newPrivateKeySigner(privateKeyHex)
 	if err != nil {
-		return nil, fmt.Errorf("private key parsing error: %w", err)
+		return nil, err
 	}
 
-	// ToAddress
-	to := common.HexToAddress(toAddress)
+	return NewLegacyTransactionWithSigner(client, privateKeySigner, signer.PrivateKeyPath, toAddress, value, data, gasLimit)
+}
 
+// NewLegacyTransactionWithSigner creates a new Legacy transaction signed by the key at path of the signer
+func NewLegacyTransactionWithSigner(
+	client *ethclient.Client,
+	s signer.Signer,
+	path string,
+	toAddress string,
+	value *big.Int,
+	data []byte,
+	gasLimit uint64,
+) (*LegacyTransaction, error) {
 	// Nonce
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, fmt.Errorf("failed to convert to ECDSA public key")
+	fromAddress, err := s.Address(context.Background(), path)
+	if err != nil {
+		return nil, fmt.Errorf("error getting signer address: %w", err)
 	}
-
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-	nonce, err := client.PendingNonceAt(context.Background(), fromAddress)
+	nonce, err := client.PendingNonceAt(context.Background(), common.HexToAddress(fromAddress))
 	if err != nil {
 		return nil, fmt.Errorf("error getting nonce: %w", err)
 	}
@@ -98,22 +105,41 @@ func NewLegacyTransaction(
 		return nil, fmt.Errorf("error getting chain id: %v", err)
 	}
 
+	tx := NewUnsignedLegacyTransaction(nonce, toAddress, value, data, gasPrice, gasLimit, chainID)
+	tx.client = client
+	tx.signer = s
+	tx.path = path
+	return tx, nil
+}
+
+// NewUnsignedLegacyTransaction creates a Legacy transaction without client and signer,
+// to be signed offline, e.g. with ethsig. Send and Confirm are not available.
+func NewUnsignedLegacyTransaction(
+	nonce uint64,
+	toAddress string,
+	value *big.Int,
+	data []byte,
+	gasPrice *big.Int,
+	gasLimit uint64,
+	chainID *big.Int,
+) *LegacyTransaction {
 	return &LegacyTransaction{
-		client:     client,
-		privateKey: privateKey,
-		to:         to,
-		value:      value,
-		data:       data,
-		nonce:      nonce,
-		gasPrice:   gasPrice,
-		gasLimit:   gasLimit,
-		chainID:    chainID,
-	}, nil
+		to:       common.HexToAddress(toAddress),
+		value:    value,
+		data:     data,
+		nonce:    nonce,
+		gasPrice: gasPrice,
+		gasLimit: gasLimit,
+		chainID:  chainID,
+	}
 }
 
-// Send broadcasts the transaction
-func (tx *LegacyTransaction) Send(ctx context.Context) (string, error) {
-	// Create legacy transaction
+// Sign signs the transaction with the EIP-155 replay protection of the chain
+func (tx *LegacyTransaction) Sign(ctx context.Context) (*types.Transaction, error) {
+	if tx.signer == nil {
+		return nil, fmt.Errorf("Missing signer: transaction must be created with a signer")
+	}
+
 	rawTx := types.NewTransaction(
 		tx.nonce,
 		tx.to,
@@ -122,11 +148,19 @@ func (tx *LegacyTransaction) Send(ctx context.Context) (string, error) {
 		tx.gasPrice,
 		tx.data,
 	)
+	signedTx, err := tx.signer.SignTx(ctx, tx.path, rawTx, tx.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("transaction signing error: %w", err)
+	}
+	return signedTx, nil
+}
 
+// Send broadcasts the transaction
+func (tx *LegacyTransaction) Send(ctx context.Context) (string, error) {
 	// Sign transaction
-	signedTx, err := types.SignTx(rawTx, types.NewEIP155Signer(tx.chainID), tx.privateKey)
+	signedTx, err := tx.Sign(ctx)
 	if err != nil {
-		return "", fmt.Errorf("transaction signing error: %w", err)
+		return "", err
 	}
 
 	// Send transaction
@@ -3,12 +3,17 @@ package ethtx
 import (
 	"context"
 	"fmt"
-	"log"
 	"math/big"
 	"testing"
 	"time"
 	
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/01-mnemonic/mnemonic"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/05-signature/signer"
 )
 
 var (
@@ -18,11 +23,69 @@ var (
 	blockConfirmations = uint64(1)
 )
 
+func TestSign(t *testing.T) {
+	chainID := big.NewInt(11155111)
+	value := big.NewInt(100000000000000)
+
+	privateKeySigner, err := newPrivateKeySigner(privateKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, _ := crypto.HexToECDSA(privateKeyHex)
+
+	master, _ := key.NewMasterFromSeed(mnemonic.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", ""))
+	hdSigner, _ := signer.NewHDSigner(master)
+
+	tests := []struct {
+		signer signer.Signer
+		path   string
+		sender common.Address
+	}{
+		{privateKeySigner, signer.PrivateKeyPath, crypto.PubkeyToAddress(privateKey.PublicKey)},
+		{hdSigner, "m/44'/60'/0'/0/0", common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")},
+	}
+	for _, test := range tests {
+		legacyTx := NewUnsignedLegacyTransaction(1, toAddressHex, value, nil, big.NewInt(20000000000), 21000, chainID)
+		legacyTx.signer, legacyTx.path = test.signer, test.path
+		eip1559Tx := &EIP1559Transaction{
+			signer:               test.signer,
+			path:                 test.path,
+			to:                   common.HexToAddress(toAddressHex),
+			value:                value,
+			nonce:                1,
+			maxPriorityFeePerGas: big.NewInt(1000000000),
+			maxFeePerGas:         big.NewInt(20000000000),
+			gasLimit:             21000,
+			chainID:              chainID,
+		}
+
+		for _, sign := range []func(context.Context) (*types.Transaction, error){legacyTx.Sign, eip1559Tx.Sign} {
+			signedTx, err := sign(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sender != test.sender {
+				t.Fatalf("%s: got sender %s, want %s", test.path, sender.Hex(), test.sender.Hex())
+			}
+		}
+	}
+
+	// an unsigned transaction has no signer
+	_, err = NewUnsignedLegacyTransaction(1, toAddressHex, value, nil, big.NewInt(20000000000), 21000, chainID).Sign(context.Background())
+	if err == nil {
+		t.Fatal("missing signer: expected error")
+	}
+}
+
 func TestETHTransfer(t *testing.T) {
 	// Connect to Ethereum client
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
-		log.Fatalf("Failed to connect to Ethereum client: %v", err)
+		t.Skipf("Failed to connect to Ethereum client: %v", err)
 	}
 
 	// Private key (in production, should be retrieved from environment variables or secure storage)
@@ -47,7 +110,7 @@ func TestETHTransfer(t *testing.T) {
 		gasLimit,
 	)
 	if err != nil {
-		log.Fatalf("Failed to create transaction: %v", err)
+		t.Fatalf("Failed to create transaction: %v", err)
 	}
 	
 	// Send transaction
@@ -56,17 +119,17 @@ func TestETHTransfer(t *testing.T) {
 	
 	_, err = tx.Send(ctx)
 	if err != nil {
-		log.Fatalf("Failed to send transaction: %v", err)
+		t.Fatalf("Failed to send transaction: %v", err)
 	}
 	
 	// Confirm transaction (wait for 3 block confirmations)
 	receipt, err := tx.Confirm(ctx, blockConfirmations)
 	if err != nil {
-		log.Fatalf("Failed to confirm transaction: %v", err)
+		t.Fatalf("Failed to confirm transaction: %v", err)
 	}
 	
 	// Output receipt information
 	fmt.Printf("Transaction successfully confirmed.\n")
 	fmt.Printf("Gas used: %d\n", receipt.GasUsed)
 	fmt.Printf("Status: %d\n", receipt.Status)
-}
\ No newline at end of file
+}
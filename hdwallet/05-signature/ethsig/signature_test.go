@@ -7,11 +7,15 @@ import (
 	"log"
 	"fmt"
 	"math/big"
+	"encoding/hex"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
 	"github.com/boxwood-zip/learning-blockchain/hdwallet/04-transaction/ethtx"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/05-signature/signer"
 )
 
 var (
@@ -21,17 +25,59 @@ var (
 	blockConfirmations = uint64(1)
 )
 
+func TestSignTxWithSigner(t *testing.T) {
+	chainID := big.NewInt(11155111)
+	gasPrice := big.NewInt(20000000000)
+	value := big.NewInt(100000000000000)
+	tx := ethtx.NewUnsignedLegacyTransaction(7, toAddressHex, value, nil, gasPrice, 21000, chainID)
+	es := NewEIP155Signer(chainID)
+
+	// reference signature of go-ethereum
+	privateKey, _ := crypto.HexToECDSA(privateKeyHex)
+	rawTx := types.NewTransaction(7, tx.To(), value, 21000, gasPrice, nil)
+	referenceTx, err := types.SignTx(rawTx, types.NewEIP155Signer(chainID), privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reference, _ := referenceTx.MarshalBinary()
+	want := "0x" + hex.EncodeToString(reference)
+
+	signedTxHex, err := SignTx(tx, es, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signedTxHex != want {
+		t.Fatalf("SignTx: got %s, want %s", signedTxHex, want)
+	}
+
+	privateKeyBytes, _ := hex.DecodeString(privateKeyHex)
+	k, _ := key.PrivateKeyFromByte(privateKeyBytes)
+	privateKeySigner, _ := signer.NewPrivateKeySigner(k)
+	signedTxHex, err = SignTxWithSigner(context.Background(), tx, es, privateKeySigner, signer.PrivateKeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signedTxHex != want {
+		t.Fatalf("SignTxWithSigner: got %s, want %s", signedTxHex, want)
+	}
+
+	_, err = SignTxWithSigner(context.Background(), tx, es, privateKeySigner, "m/0")
+	if err == nil {
+		t.Fatal("path of private key signer: expected error")
+	}
+}
+
 func TestEIP155Signer(t *testing.T) {
 	// Connect to Ethereum client
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
-		log.Fatalf("Failed to connect to Ethereum client: %v", err)
+		t.Skipf("Failed to connect to Ethereum client: %v", err)
 	}
 
 	// Private key (in production, should be retrieved from environment variables or secure storage)
 	privateKey, err := crypto.HexToECDSA(privateKeyHex)
 	if err != nil {
-		log.Fatalf("Convert private key failed: %v", err)
+		t.Fatalf("Convert private key failed: %v", err)
 	}
 	
 	// Recipient address
@@ -53,13 +99,13 @@ func TestEIP155Signer(t *testing.T) {
 		gasLimit,
 	)
 	if err != nil {
-		log.Fatalf("Failed to create transaction: %v", err)
+		t.Fatalf("Failed to create transaction: %v", err)
 	}
 
 	// transaction signature
 	signedTxHex, err:= SignTx(tx, NewEIP155Signer(tx.ChainID()), privateKey)
 	if err != nil {
-		log.Fatalf("signature failed: %v", err)
+		t.Fatalf("signature failed: %v", err)
 	}
 	log.Println("signed transaction: ", signedTxHex)
 
@@ -69,12 +115,12 @@ func TestEIP155Signer(t *testing.T) {
 
 	rpcClient, err := rpc.Dial(rpcURL)
 	if err != nil {
-		log.Fatalf("Contect RPC failed: %v", err)
+		t.Fatalf("Contect RPC failed: %v", err)
 	}
 
 	txHash, err := tx.SendRaw(rpcClient, ctx, signedTxHex)
 	if err != nil {
-		log.Fatalf("transaction send failed: %v", err)
+		t.Fatalf("transaction send failed: %v", err)
 	}
 
 	fmt.Println("transaction has been sent.")
@@ -83,11 +129,11 @@ func TestEIP155Signer(t *testing.T) {
 	// Confirm transaction (wait for 3 block confirmations)
 	receipt, err := tx.Confirm(ctx, blockConfirmations)
 	if err != nil {
-		log.Fatalf("Failed to confirm transaction: %v", err)
+		t.Fatalf("Failed to confirm transaction: %v", err)
 	}
 	
 	// Output receipt information
 	fmt.Printf("Transaction successfully confirmed.\n")
 	fmt.Printf("Gas used: %d\n", receipt.GasUsed)
 	fmt.Printf("Status: %d\n", receipt.Status)
-}
\ No newline at end of file
+}
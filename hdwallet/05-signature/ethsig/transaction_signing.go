@@ -3,25 +3,43 @@ package ethsig
 import (
 	"fmt"
 	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"encoding/hex"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
 	"github.com/boxwood-zip/learning-blockchain/hdwallet/04-transaction/ethtx"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/05-signature/signer"
 )
 
 type Signer = EIP155Signer
 
 // SignTx returns legacy transaction signed with EIP155 standard.
-func SignTx(tx *ethtx.LegacyTransaction, signer Signer, privateKey *ecdsa.PrivateKey) (string, error) {
-	h := signer.Hash(tx)
-	sig, err := crypto.Sign(h[:], privateKey)
+func SignTx(tx *ethtx.LegacyTransaction, es Signer, privateKey *ecdsa.PrivateKey) (string, error) {
+	k, err := key.PrivateKeyFromByte(crypto.FromECDSA(privateKey))
+	if err != nil {
+		return "", fmt.Errorf("signature failed: %v", err)
+	}
+	s, err := signer.NewPrivateKeySigner(k)
+	if err != nil {
+		return "", fmt.Errorf("signature failed: %v", err)
+	}
+
+	return SignTxWithSigner(context.Background(), tx, es, s, signer.PrivateKeyPath)
+}
+
+// SignTxWithSigner returns legacy transaction signed with EIP155 standard by the key
+// at path of a signer.Signer, so the private key can stay out of process.
+func SignTxWithSigner(ctx context.Context, tx *ethtx.LegacyTransaction, es Signer, s signer.Signer, path string) (string, error) {
+	h := es.Hash(tx)
+	sig, err := s.SignDigest(ctx, path, h[:])
 	if err != nil {
 		return "", fmt.Errorf("signature failed: %v", err)
 	}
 
-	return ApplySignature(tx, sig, signer), nil
+	return ApplySignature(tx, sig, es), nil
 }
 
 // ApplySignature encodes legacy transaction with signature data.
@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/03-address/address"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// HDSigner signs in process with keys derived from a master extended key
+type HDSigner struct {
+	master *key.ExtendedKey
+}
+
+// NewHDSigner returns a signer deriving keys from the master private key
+func NewHDSigner(master *key.ExtendedKey) (*HDSigner, error) {
+	if master == nil || !master.IsPrivate() {
+		return nil, errors.New("Invalid master key: signer requires a private extended key")
+	}
+	return &HDSigner{master: master}, nil
+}
+
+func (s *HDSigner) PublicKey(ctx context.Context, path string) (*key.PublicKey, error) {
+	derived, err := s.master.DerivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	defer s.zero(derived)
+	return derived.PublicKey(), nil
+}
+
+func (s *HDSigner) Address(ctx context.Context, path string) (string, error) {
+	publicKey, err := s.PublicKey(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return address.ToEIP55Address(publicKey)
+}
+
+func (s *HDSigner) SignDigest(ctx context.Context, path string, digest []byte) ([]byte, error) {
+	if len(digest) != 32 {
+		return nil, errors.New("Invalid digest length: must be 32 bytes")
+	}
+
+	derived, err := s.master.DerivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	defer s.zero(derived)
+
+	return signDigest(derived.PrivateKey(), digest), nil
+}
+
+func (s *HDSigner) SignTx(ctx context.Context, path string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return signTx(ctx, s, path, tx, chainID)
+}
+
+// zero wipes a derived key, never the master key itself
+func (s *HDSigner) zero(derived *key.ExtendedKey) {
+	if derived != s.master {
+		derived.Zero()
+	}
+}
@@ -0,0 +1,56 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/03-address/address"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PrivateKeyPath is the only path a PrivateKeySigner signs at
+const PrivateKeyPath = "m"
+
+// PrivateKeySigner signs in process with a single private key, e.g. one imported from hex
+type PrivateKeySigner struct {
+	privateKey *key.PrivateKey
+}
+
+// NewPrivateKeySigner returns a signer for the private key at PrivateKeyPath
+func NewPrivateKeySigner(privateKey *key.PrivateKey) (*PrivateKeySigner, error) {
+	if privateKey == nil {
+		return nil, errors.New("Invalid private key: signer requires a private key")
+	}
+	return &PrivateKeySigner{privateKey: privateKey}, nil
+}
+
+func (s *PrivateKeySigner) PublicKey(ctx context.Context, path string) (*key.PublicKey, error) {
+	if path != PrivateKeyPath {
+		return nil, errors.New("Invalid derivation path: private key signer only holds the key at " + PrivateKeyPath)
+	}
+	return s.privateKey.PublicKey(), nil
+}
+
+func (s *PrivateKeySigner) Address(ctx context.Context, path string) (string, error) {
+	publicKey, err := s.PublicKey(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return address.ToEIP55Address(publicKey)
+}
+
+func (s *PrivateKeySigner) SignDigest(ctx context.Context, path string, digest []byte) ([]byte, error) {
+	if len(digest) != 32 {
+		return nil, errors.New("Invalid digest length: must be 32 bytes")
+	}
+	if path != PrivateKeyPath {
+		return nil, errors.New("Invalid derivation path: private key signer only holds the key at " + PrivateKeyPath)
+	}
+	return signDigest(s.privateKey, digest), nil
+}
+
+func (s *PrivateKeySigner) SignTx(ctx context.Context, path string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return signTx(ctx, s, path, tx, chainID)
+}
@@ -0,0 +1,135 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// namespace prefixes the JSON-RPC methods of the private protocol, e.g. hdwallet_signDigest.
+// Keys are selected by derivation path, which external signers such as Clef (account_*)
+// or Web3Signer do not support, so both ends must use this package.
+const namespace = "hdwallet"
+
+// RemoteSigner forwards every request over the private hdwallet_* JSON-RPC protocol
+// to a signing daemon served by NewServer, so private keys never enter the application process
+type RemoteSigner struct {
+	client *rpc.Client
+}
+
+// DialRemoteSigner connects to a signing daemon by IPC socket path, http(s) or ws(s) URL
+func DialRemoteSigner(endpoint string) (*RemoteSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewRemoteSigner(client), nil
+}
+
+func NewRemoteSigner(client *rpc.Client) *RemoteSigner {
+	return &RemoteSigner{client: client}
+}
+
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+func (s *RemoteSigner) PublicKey(ctx context.Context, path string) (*key.PublicKey, error) {
+	var publicKey hexutil.Bytes
+	err := s.client.CallContext(ctx, &publicKey, namespace+"_publicKey", path)
+	if err != nil {
+		return nil, err
+	}
+	return key.PublicKeyFromByte(publicKey)
+}
+
+func (s *RemoteSigner) Address(ctx context.Context, path string) (string, error) {
+	var address string
+	err := s.client.CallContext(ctx, &address, namespace+"_address", path)
+	return address, err
+}
+
+func (s *RemoteSigner) SignDigest(ctx context.Context, path string, digest []byte) ([]byte, error) {
+	var sig hexutil.Bytes
+	err := s.client.CallContext(ctx, &sig, namespace+"_signDigest", path, hexutil.Bytes(digest))
+	if err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignTx sends the unsigned transaction to the daemon, which signs it with its own
+// copy of the transaction hash, and returns the signed transaction
+func (s *RemoteSigner) SignTx(ctx context.Context, path string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	unsigned, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var signed hexutil.Bytes
+	err = s.client.CallContext(ctx, &signed, namespace+"_signTransaction", path, hexutil.Bytes(unsigned), (*hexutil.Big)(chainID))
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx := new(types.Transaction)
+	err = signedTx.UnmarshalBinary(signed)
+	if err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+// Service exposes a Signer over JSON-RPC for a signing daemon
+type Service struct {
+	signer Signer
+}
+
+// NewServer returns a JSON-RPC server serving the signer under the private hdwallet namespace.
+// Serve it with ServeListener on an IPC socket or as an http.Handler.
+func NewServer(s Signer) (*rpc.Server, error) {
+	server := rpc.NewServer()
+	err := server.RegisterName(namespace, &Service{signer: s})
+	if err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+func (s *Service) PublicKey(ctx context.Context, path string) (hexutil.Bytes, error) {
+	publicKey, err := s.signer.PublicKey(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return publicKey.Serialize(), nil
+}
+
+func (s *Service) Address(ctx context.Context, path string) (string, error) {
+	return s.signer.Address(ctx, path)
+}
+
+func (s *Service) SignDigest(ctx context.Context, path string, digest hexutil.Bytes) (hexutil.Bytes, error) {
+	return s.signer.SignDigest(ctx, path, digest)
+}
+
+func (s *Service) SignTransaction(ctx context.Context, path string, unsigned hexutil.Bytes, chainID *hexutil.Big) (hexutil.Bytes, error) {
+	if chainID == nil {
+		return nil, errors.New("Invalid chain ID: chain ID is required")
+	}
+	tx := new(types.Transaction)
+	err := tx.UnmarshalBinary(unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx, err := s.signer.SignTx(ctx, path, tx, chainID.ToInt())
+	if err != nil {
+		return nil, err
+	}
+	return signedTx.MarshalBinary()
+}
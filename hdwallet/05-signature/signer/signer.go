@@ -0,0 +1,50 @@
+package signer
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Signer signs with keys identified by a derivation path, so the keys can live
+// in memory, on a hardware wallet or in a separate signing daemon
+type Signer interface {
+	// PublicKey returns the public key at the derivation path
+	PublicKey(ctx context.Context, path string) (*key.PublicKey, error)
+	// Address returns the EIP-55 address of the key at the derivation path
+	Address(ctx context.Context, path string) (string, error)
+	// SignDigest signs a 32-byte digest and returns the 65-byte [R || S || V]
+	// signature with V 0 or 1, as produced by go-ethereum's crypto.Sign
+	SignDigest(ctx context.Context, path string, digest []byte) ([]byte, error)
+	// SignTx signs the transaction for the chain with the latest signer of the chain
+	SignTx(ctx context.Context, path string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// signTx hashes the transaction and attaches a signature over the hash
+func signTx(ctx context.Context, s Signer, path string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	txSigner := types.LatestSignerForChainID(chainID)
+	h := txSigner.Hash(tx)
+	sig, err := s.SignDigest(ctx, path, h[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(txSigner, sig)
+}
+
+// signDigest signs the 32-byte digest and returns [R || S || V] with V 0 or 1
+func signDigest(k *key.PrivateKey, digest []byte) []byte {
+	privateKeyByte := k.Serialize()
+	privateKey, _ := btcec.PrivKeyFromBytes(privateKeyByte)
+	for i := range privateKeyByte {
+		privateKeyByte[i] = 0
+	}
+	defer privateKey.Zero()
+
+	// compact signatures are [27 + recovery id || R || S]
+	compact := ecdsa.SignCompact(privateKey, digest, false)
+	return append(compact[1:], compact[0]-27)
+}
@@ -0,0 +1,166 @@
+package signer
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/01-mnemonic/mnemonic"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	testPath     = "m/44'/60'/0'/0/0"
+	testAddress  = "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+)
+
+func newTestHDSigner(t *testing.T) *HDSigner {
+	seed := mnemonic.NewSeed(testMnemonic, "")
+	master, err := key.NewMasterFromSeed(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewHDSigner(master)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// testSigner checks a signer against the well-known first Ethereum account of the test mnemonic
+func testSigner(t *testing.T, s Signer) {
+	ctx := context.Background()
+
+	address, err := s.Address(ctx, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != testAddress {
+		t.Fatalf("got %s, want %s", address, testAddress)
+	}
+
+	publicKey, err := s.PublicKey(ctx, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*publicKey.ToECDSA()).Hex() != testAddress {
+		t.Fatalf("public key does not match %s", testAddress)
+	}
+
+	digest := crypto.Keccak256([]byte("message"))
+	sig, err := s.SignDigest(ctx, testPath, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := crypto.SigToPub(digest, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*recovered).Hex() != testAddress {
+		t.Fatalf("signature recovers to %s, want %s", crypto.PubkeyToAddress(*recovered).Hex(), testAddress)
+	}
+
+	chainID := big.NewInt(11155111)
+	to := common.HexToAddress("0xD8Ea779b8FFC1096CA422D40588C4c0641709890")
+	txs := []*types.Transaction{
+		types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     1,
+			GasTipCap: big.NewInt(1000000000),
+			GasFeeCap: big.NewInt(20000000000),
+			Gas:       21000,
+			To:        &to,
+			Value:     big.NewInt(100000000000000),
+		}),
+		types.NewTx(&types.LegacyTx{
+			Nonce:    2,
+			GasPrice: big.NewInt(20000000000),
+			Gas:      21000,
+			To:       &to,
+			Value:    big.NewInt(100000000000000),
+		}),
+	}
+	for _, tx := range txs {
+		signedTx, err := s.SignTx(ctx, testPath, tx, chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sender.Hex() != testAddress {
+			t.Fatalf("got sender %s, want %s", sender.Hex(), testAddress)
+		}
+		if signedTx.ChainId().Cmp(chainID) != 0 {
+			t.Fatalf("got chain id %v, want %v", signedTx.ChainId(), chainID)
+		}
+	}
+
+	_, err = s.SignDigest(ctx, testPath, digest[:31])
+	if err == nil {
+		t.Fatal("31-byte digest: expected error")
+	}
+	_, err = s.Address(ctx, "m/44'/60'/x")
+	if err == nil {
+		t.Fatal("invalid path: expected error")
+	}
+}
+
+func TestHDSigner(t *testing.T) {
+	testSigner(t, newTestHDSigner(t))
+
+	seed := mnemonic.NewSeed(testMnemonic, "")
+	master, _ := key.NewMasterFromSeed(seed)
+	_, err := NewHDSigner(master.Neuter())
+	if err == nil {
+		t.Fatal("public master key: expected error")
+	}
+
+	// using the master key itself must not wipe it for later derivations
+	s := newTestHDSigner(t)
+	ctx := context.Background()
+	_, err = s.Address(ctx, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.SignDigest(ctx, "m", crypto.Keccak256([]byte("message")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testSigner(t, s)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	server, err := NewServer(newTestHDSigner(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	// the daemon listens on a unix socket
+	endpoint := filepath.Join(t.TempDir(), "signer.ipc")
+	listener, err := net.Listen("unix", endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.ServeListener(listener)
+
+	remote, err := DialRemoteSigner(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Close()
+	testSigner(t, remote)
+
+	inProc := NewRemoteSigner(rpc.DialInProc(server))
+	defer inProc.Close()
+	testSigner(t, inProc)
+}
@@ -0,0 +1,165 @@
+package bip38
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/00-encoding/base58"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/03-address/address"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	encryptedKeyLength = 39
+
+	flagNonECMultiplied = 0xC0
+	flagCompressed      = 0x20
+	flagLotSequence     = 0x04
+
+	scryptN = 16384
+	scryptR = 8
+	scryptP = 8
+)
+
+var (
+	prefixNonECMultiplied = []byte{0x01, 0x42}
+	prefixECMultiplied    = []byte{0x01, 0x43}
+)
+
+var ErrInvalidPassphrase = errors.New("Invalid passphrase: address hash mismatch")
+
+// ErrInvalidChecksum is returned for a corrupted Base58Check string
+var ErrInvalidChecksum = base58.ErrInvalidChecksum
+
+// Encrypt encrypts the private key with the passphrase without EC multiplication.
+// The result starts with 6P and decrypts to a compressed or uncompressed key.
+func Encrypt(privateKey *key.PrivateKey, passphrase string, compressed bool) (string, error) {
+	addressHash := addressHashOf(privateKey.PublicKey(), compressed)
+	derived, err := scrypt.Key(normalize(passphrase), addressHash, scryptN, scryptR, scryptP, 64)
+	if err != nil {
+		return "", err
+	}
+	derivedHalf1, derivedHalf2 := derived[:32], derived[32:]
+
+	block, err := aes.NewCipher(derivedHalf2)
+	if err != nil {
+		return "", err
+	}
+	encrypted := xor(privateKey.Serialize(), derivedHalf1)
+	block.Encrypt(encrypted[:16], encrypted[:16])
+	block.Encrypt(encrypted[16:], encrypted[16:])
+
+	flag := byte(flagNonECMultiplied)
+	if compressed {
+		flag |= flagCompressed
+	}
+	payload := append(append([]byte(nil), prefixNonECMultiplied...), flag)
+	payload = append(payload, addressHash...)
+	payload = append(payload, encrypted...)
+	return base58.CheckEncode(payload), nil
+}
+
+// Decrypt decrypts a BIP-38 key of either mode and verifies it against the
+// address hash. The key is returned as mainnet WIF with its compression flag.
+func Decrypt(encrypted string, passphrase string) (*key.WIF, error) {
+	payload, err := base58.CheckDecode(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != encryptedKeyLength {
+		return nil, errors.New("Invalid encrypted key length: must be 39 bytes")
+	}
+
+	flag := payload[2]
+	compressed := flag&flagCompressed != 0
+	addressHash := payload[3:7]
+
+	var privateKey *key.PrivateKey
+	switch {
+	case bytes.Equal(payload[:2], prefixNonECMultiplied):
+		if flag&^flagCompressed != flagNonECMultiplied {
+			return nil, errors.New("Invalid flag byte: unsupported bits for a non EC-multiplied key")
+		}
+		privateKey, err = decryptNonECMultiplied(payload, passphrase)
+	case bytes.Equal(payload[:2], prefixECMultiplied):
+		if flag&^(flagCompressed|flagLotSequence) != 0 {
+			return nil, errors.New("Invalid flag byte: unsupported bits for an EC-multiplied key")
+		}
+		privateKey, err = decryptECMultiplied(payload, passphrase)
+	default:
+		return nil, errors.New("Invalid encrypted key prefix: must be 0x0142 or 0x0143")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(addressHash, addressHashOf(privateKey.PublicKey(), compressed)) {
+		return nil, ErrInvalidPassphrase
+	}
+	return &key.WIF{PrivateKey: privateKey, Compressed: compressed}, nil
+}
+
+func decryptNonECMultiplied(payload []byte, passphrase string) (*key.PrivateKey, error) {
+	derived, err := scrypt.Key(normalize(passphrase), payload[3:7], scryptN, scryptR, scryptP, 64)
+	if err != nil {
+		return nil, err
+	}
+	derivedHalf1, derivedHalf2 := derived[:32], derived[32:]
+
+	block, err := aes.NewCipher(derivedHalf2)
+	if err != nil {
+		return nil, err
+	}
+	decrypted := make([]byte, 32)
+	block.Decrypt(decrypted[:16], payload[7:23])
+	block.Decrypt(decrypted[16:], payload[23:39])
+
+	privateKeyBytes := xor(decrypted, derivedHalf1)
+	if !validScalar(privateKeyBytes) {
+		return nil, ErrInvalidPassphrase
+	}
+	return key.PrivateKeyFromByte(privateKeyBytes)
+}
+
+// addressHashOf returns the first 4 bytes of double SHA-256 of the P2PKH address
+func addressHashOf(publicKey *key.PublicKey, compressed bool) []byte {
+	return doubleSHA256([]byte(p2pkhAddress(publicKey, compressed)))[:4]
+}
+
+func p2pkhAddress(publicKey *key.PublicKey, compressed bool) string {
+	if compressed {
+		return address.ToCompressedP2PKHAddress(publicKey, false)
+	}
+	return address.ToP2PKHAddress(publicKey, false)
+}
+
+// normalize returns the passphrase in Unicode NFC as required by BIP-38
+func normalize(passphrase string) []byte {
+	return []byte(norm.NFC.String(passphrase))
+}
+
+func xor(a []byte, b []byte) []byte {
+	result := make([]byte, len(a))
+	for i := range a {
+		result[i] = a[i] ^ b[i]
+	}
+	return result
+}
+
+// validScalar reports whether the bytes are a private key between 1 and n-1
+func validScalar(b []byte) bool {
+	d := new(big.Int).SetBytes(b)
+	return d.Sign() > 0 && d.Cmp(btcec.S256().N) < 0
+}
+
+func doubleSHA256(data []byte) []byte {
+	firstSHA := sha256.Sum256(data)
+	secondSHA := sha256.Sum256(firstSHA[:])
+	return secondSHA[:]
+}
@@ -0,0 +1,258 @@
+package bip38
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/00-encoding/base58"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+)
+
+// nonECMultiplyVectors are the BIP-38 vectors without EC multiplication
+var nonECMultiplyVectors = []struct {
+	passphrase string
+	encrypted  string
+	wif        string
+	privateKey string
+	compressed bool
+}{
+	{
+		"TestingOneTwoThree",
+		"6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGg",
+		"5KN7MzqK5wt2TP1fQCYyHBtDrXdJuXbUzm4A9rKAteGu3Qi5CVR",
+		"cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+		false,
+	},
+	{
+		"Satoshi",
+		"6PRNFFkZc2NZ6dJqFfhRoFNMR9Lnyj7dYGrzdgXXVMXcxoKTePPX1dWByq",
+		"5HtasZ6ofTHP6HCwTqTkLDuLQisYPah7aUnSKfC7h4hMUVw2gi5",
+		"09c2686880095b1a4c249ee3ac4eea8a014f11e6f986d0b5025ac1f39afbd9ae",
+		false,
+	},
+	{
+		// GREEK UPSILON WITH HOOK, COMBINING ACUTE ACCENT, NULL, DESERET CAPITAL LETTER LONG I, PILE OF POO
+		"\u03D2\u0301\u0000\U00010400\U0001F4A9",
+		"6PRW5o9FLp4gJDDVqJQKJFTpMvdsSGJxMYHtHaQBF3ooa8mwD69bapcDQn",
+		"5Jajm8eQ22H3pGWLEVCXyvND8dQZhiQhoLJNKjYXk9roUFTMSZ4",
+		"",
+		false,
+	},
+	{
+		"TestingOneTwoThree",
+		"6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo",
+		"L44B5gGEpqEDRS9vVPz7QT35jcBG2r3CZwSwQ4fCewXAhAhqGVpP",
+		"cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+		true,
+	},
+	{
+		"Satoshi",
+		"6PYLtMnXvfG3oJde97zRyLYFZCYizPU5T3LwgdYJz1fRhh16bU7u6PPmY7",
+		"KwYgW8gcxj1JWJXhPSu4Fqwzfhp5Yfi42mdYmMa4XqK7NJxXUSK7",
+		"09c2686880095b1a4c249ee3ac4eea8a014f11e6f986d0b5025ac1f39afbd9ae",
+		true,
+	},
+}
+
+// ecMultiplyVectors are the BIP-38 vectors with EC multiplication
+var ecMultiplyVectors = []struct {
+	passphrase       string
+	intermediateCode string
+	encrypted        string
+	address          string
+	wif              string
+	confirmationCode string
+	lot              uint32
+	sequence         uint32
+}{
+	{
+		"TestingOneTwoThree",
+		"passphrasepxFy57B9v8HtUsszJYKReoNDV6VHjUSGt8EVJmux9n1J3Ltf1gRxyDGXqnf9qm",
+		"6PfQu77ygVyJLZjfvMLyhLMQbYnu5uguoJJ4kMCLqWwPEdfpwANVS76gTX",
+		"1PE6TQi6HTVNz5DLwB1LcpMBALubfuN2z2",
+		"5K4caxezwjGCGfnoPTZ8tMcJBLB7Jvyjv4xxeacadhq8nLisLR2",
+		"", 0, 0,
+	},
+	{
+		"Satoshi",
+		"passphraseoRDGAXTWzbp72eVbtUDdn1rwpgPUGjNZEc6CGBo8i5EC1FPW8wcnLdq4ThKzAS",
+		"6PfLGnQs6VZnrNpmVKfjotbnQuaJK4KZoPFrAjx1JMJUa1Ft8gnf5WxfKd",
+		"1CqzrtZC6mXSAhoxtFwVjz8LtwLJjDYU3V",
+		"5KJ51SgxWaAYR13zd9ReMhJpwrcX47xTJh2D3fGPG9CM8vkv5sH",
+		"", 0, 0,
+	},
+	{
+		"MOLON LABE",
+		"passphraseaB8feaLQDENqCgr4gKZpmf4VoaT6qdjJNJiv7fsKvjqavcJxvuR1hy25aTu5sX",
+		"6PgNBNNzDkKdhkT6uJntUXwwzQV8Rr2tZcbkDcuC9DZRsS6AtHts4Ypo1j",
+		"1Jscj8ALrYu2y9TD8NrpvDBugPedmbj4Yh",
+		"5JLdxTtcTHcfYcmJsNVy1v2PMDx432JPoYcBTVVRHpPaxUrdtf8",
+		"cfrm38V8aXBn7JWA1ESmFMUn6erxeBGZGAxJPY4e36S9QWkzZKtaVqLNMgnifETYw7BPwWC9aPD",
+		263183, 1,
+	},
+	{
+		"ΜΟΛΩΝ ΛΑΒΕ",
+		"passphrased3z9rQJHSyBkNBwTRPkUGNVEVrUAcfAXDyRU1V28ie6hNFbqDwbFBvsTK7yWVK",
+		"6PgGWtx25kUg8QWvwuJAgorN6k9FbE25rv5dMRwu5SKMnfpfVe5mar2ngH",
+		"1Lurmih3KruL4xDB5FmHof38yawNtP9oGf",
+		"5KMKKuUmAkiNbA3DazMQiLfDq47qs8MAEThm4yL8R2PhV1ov33D",
+		"cfrm38V8G4qq2ywYEFfWLD5Cc6msj9UwsG2Mj4Z6QdGJAFQpdatZLavkgRd1i4iBMdRngDqDs51",
+		806938, 1,
+	},
+}
+
+func TestNonECMultiply(t *testing.T) {
+	for _, v := range nonECMultiplyVectors {
+		wif, err := Decrypt(v.encrypted, v.passphrase)
+		if err != nil {
+			t.Fatalf("%s: %v", v.encrypted, err)
+		}
+		if wif.String() != v.wif {
+			t.Fatalf("%s: got %s, want %s", v.encrypted, wif.String(), v.wif)
+		}
+		if v.privateKey != "" && wif.PrivateKey.Hex() != "0x"+v.privateKey {
+			t.Fatalf("%s: got %s, want 0x%s", v.encrypted, wif.PrivateKey.Hex(), v.privateKey)
+		}
+
+		encrypted, err := Encrypt(wif.PrivateKey, v.passphrase, v.compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encrypted != v.encrypted {
+			t.Fatalf("got %s, want %s", encrypted, v.encrypted)
+		}
+	}
+
+	_, err := Decrypt(nonECMultiplyVectors[0].encrypted, "Satoshi")
+	if err != ErrInvalidPassphrase {
+		t.Fatalf("wrong passphrase: got %v, want %v", err, ErrInvalidPassphrase)
+	}
+}
+
+func TestECMultiply(t *testing.T) {
+	for _, v := range ecMultiplyVectors {
+		wif, err := Decrypt(v.encrypted, v.passphrase)
+		if err != nil {
+			t.Fatalf("%s: %v", v.encrypted, err)
+		}
+		if wif.String() != v.wif {
+			t.Fatalf("%s: got %s, want %s", v.encrypted, wif.String(), v.wif)
+		}
+
+		if v.confirmationCode != "" {
+			address, err := VerifyConfirmationCode(v.confirmationCode, v.passphrase)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if address != v.address {
+				t.Fatalf("%s: got %s, want %s", v.confirmationCode, address, v.address)
+			}
+		}
+
+		// the intermediate code is reproduced from its owner salt
+		payload, _ := base58.CheckDecode(v.intermediateCode)
+		var intermediate string
+		if v.confirmationCode != "" {
+			lotSequence := payload[12:16]
+			if binary.BigEndian.Uint32(lotSequence) != v.lot*4096+v.sequence {
+				t.Fatalf("%s: unexpected lot and sequence", v.intermediateCode)
+			}
+			intermediate, err = intermediateCode(v.passphrase, payload[8:12], lotSequence)
+		} else {
+			intermediate, err = intermediateCode(v.passphrase, payload[8:16], nil)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if intermediate != v.intermediateCode {
+			t.Fatalf("got %s, want %s", intermediate, v.intermediateCode)
+		}
+	}
+
+	_, err := VerifyConfirmationCode(ecMultiplyVectors[2].confirmationCode, "MOLON LABE!")
+	if err == nil {
+		t.Fatal("wrong passphrase: expected error")
+	}
+}
+
+func TestEncryptFromIntermediateCode(t *testing.T) {
+	tests := []struct {
+		lotSequence bool
+		compressed  bool
+	}{
+		{false, false},
+		{true, true},
+	}
+	for _, test := range tests {
+		var intermediate string
+		var err error
+		if test.lotSequence {
+			intermediate, err = NewIntermediateCodeWithLotSequence("passphrase", 263183, 1)
+		} else {
+			intermediate, err = NewIntermediateCode("passphrase")
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(intermediate, "passphrase") {
+			t.Fatalf("unexpected intermediate code %s", intermediate)
+		}
+
+		generated, err := EncryptFromIntermediateCode(intermediate, test.compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(generated.EncryptedKey, "6P") || !strings.HasPrefix(generated.ConfirmationCode, "cfrm38") {
+			t.Fatalf("unexpected generated key %+v", generated)
+		}
+
+		address, err := VerifyConfirmationCode(generated.ConfirmationCode, "passphrase")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if address != generated.Address {
+			t.Fatalf("got %s, want %s", address, generated.Address)
+		}
+
+		wif, err := Decrypt(generated.EncryptedKey, "passphrase")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wif.Compressed != test.compressed {
+			t.Fatalf("got compressed %v, want %v", wif.Compressed, test.compressed)
+		}
+		if p2pkhAddress(wif.PrivateKey.PublicKey(), test.compressed) != generated.Address {
+			t.Fatalf("decrypted key does not match %s", generated.Address)
+		}
+	}
+
+	_, err := NewIntermediateCodeWithLotSequence("passphrase", MaxLot+1, 0)
+	if err == nil {
+		t.Fatal("lot out of range: expected error")
+	}
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	privateKeyBytes, _ := hex.DecodeString("cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5")
+	privateKey, _ := key.PrivateKeyFromByte(privateKeyBytes)
+	wif := key.EncodeWIF(privateKey, false, false)
+
+	tests := []string{
+		// checksum mismatch
+		"6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGh",
+		// a WIF key is not an encrypted key
+		wif,
+		// unknown prefix
+		base58.CheckEncode(append([]byte{0x01, 0x44, 0xC0}, make([]byte, 36)...)),
+		// unsupported flag bits
+		base58.CheckEncode(append([]byte{0x01, 0x42, 0xC1}, make([]byte, 36)...)),
+	}
+	for _, test := range tests {
+		_, err := Decrypt(test, "TestingOneTwoThree")
+		if err == nil {
+			t.Fatalf("%s: expected error", test)
+		}
+	}
+}
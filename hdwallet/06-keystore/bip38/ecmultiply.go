@@ -0,0 +1,297 @@
+package bip38
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/00-encoding/base58"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	intermediateCodeLength = 49
+	confirmationCodeLength = 51
+
+	// MaxLot and MaxSequence bound the lot and sequence numbers of an intermediate code
+	MaxLot      = 1048575
+	MaxSequence = 4095
+)
+
+var (
+	magicLotSequence   = []byte{0x2C, 0xE9, 0xB3, 0xE1, 0xFF, 0x39, 0xE2, 0x51}
+	magicNoLotSequence = []byte{0x2C, 0xE9, 0xB3, 0xE1, 0xFF, 0x39, 0xE2, 0x53}
+	magicConfirmation  = []byte{0x64, 0x3B, 0xF6, 0xA8, 0x9A}
+)
+
+// GeneratedKey is an encrypted key generated from an intermediate code by a
+// party that never learns the passphrase or the private key
+type GeneratedKey struct {
+	// EncryptedKey starts with 6P and is decrypted with the passphrase
+	EncryptedKey string
+	// ConfirmationCode starts with cfrm38 and proves the key belongs to the passphrase
+	ConfirmationCode string
+	// Address is the P2PKH address of the key
+	Address string
+}
+
+// NewIntermediateCode derives an intermediate passphrase code from the
+// passphrase and a random owner salt. It starts with "passphrase".
+func NewIntermediateCode(passphrase string) (string, error) {
+	ownerSalt := make([]byte, 8)
+	_, err := rand.Read(ownerSalt)
+	if err != nil {
+		return "", err
+	}
+	return intermediateCode(passphrase, ownerSalt, nil)
+}
+
+// NewIntermediateCodeWithLotSequence derives an intermediate passphrase code
+// embedding a lot (0 to 1048575) and sequence (0 to 4095) number
+func NewIntermediateCodeWithLotSequence(passphrase string, lot uint32, sequence uint32) (string, error) {
+	if lot > MaxLot {
+		return "", errors.New("Invalid lot number: must be at most 1048575")
+	}
+	if sequence > MaxSequence {
+		return "", errors.New("Invalid sequence number: must be at most 4095")
+	}
+
+	ownerSalt := make([]byte, 4)
+	_, err := rand.Read(ownerSalt)
+	if err != nil {
+		return "", err
+	}
+	lotSequence := binary.BigEndian.AppendUint32(nil, lot*4096+sequence)
+	return intermediateCode(passphrase, ownerSalt, lotSequence)
+}
+
+func intermediateCode(passphrase string, ownerSalt []byte, lotSequence []byte) (string, error) {
+	ownerEntropy := append(append([]byte(nil), ownerSalt...), lotSequence...)
+	passFactor, err := passFactor(passphrase, ownerEntropy, lotSequence != nil)
+	if err != nil {
+		return "", err
+	}
+
+	magic := magicNoLotSequence
+	if lotSequence != nil {
+		magic = magicLotSequence
+	}
+	payload := append(append([]byte(nil), magic...), ownerEntropy...)
+	payload = append(payload, passPoint(passFactor)...)
+	return base58.CheckEncode(payload), nil
+}
+
+// EncryptFromIntermediateCode generates a new random key from the intermediate code
+func EncryptFromIntermediateCode(intermediate string, compressed bool) (*GeneratedKey, error) {
+	payload, err := base58.CheckDecode(intermediate)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != intermediateCodeLength {
+		return nil, errors.New("Invalid intermediate code length: must be 49 bytes")
+	}
+
+	var flag byte
+	switch {
+	case bytes.Equal(payload[:8], magicLotSequence):
+		flag = flagLotSequence
+	case bytes.Equal(payload[:8], magicNoLotSequence):
+		flag = 0
+	default:
+		return nil, errors.New("Invalid intermediate code: unknown magic bytes")
+	}
+	if compressed {
+		flag |= flagCompressed
+	}
+	ownerEntropy := payload[8:16]
+	parsedPassPoint, err := btcec.ParsePubKey(payload[16:49])
+	if err != nil {
+		return nil, err
+	}
+
+	seedB := make([]byte, 24)
+	_, err = rand.Read(seedB)
+	if err != nil {
+		return nil, err
+	}
+	factorB := doubleSHA256(seedB)
+	if !validScalar(factorB) {
+		return nil, errors.New("Invalid seed: factorb is out of range, retry")
+	}
+
+	generatedKey, err := multiply(parsedPassPoint, factorB)
+	if err != nil {
+		return nil, err
+	}
+	addressHash := addressHashOf(generatedKey, compressed)
+
+	derivedHalf1, derivedHalf2, err := deriveECMultiplied(payload[16:49], addressHash, ownerEntropy)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(derivedHalf2)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedPart1 := xor(seedB[:16], derivedHalf1[:16])
+	block.Encrypt(encryptedPart1, encryptedPart1)
+	encryptedPart2 := xor(append(append([]byte(nil), encryptedPart1[8:16]...), seedB[16:24]...), derivedHalf1[16:32])
+	block.Encrypt(encryptedPart2, encryptedPart2)
+
+	encrypted := append(append([]byte(nil), prefixECMultiplied...), flag)
+	encrypted = append(encrypted, addressHash...)
+	encrypted = append(encrypted, ownerEntropy...)
+	encrypted = append(encrypted, encryptedPart1[:8]...)
+	encrypted = append(encrypted, encryptedPart2...)
+
+	// the confirmation code encrypts pointb = factorb * G
+	pointB := passPoint(factorB)
+	pointBX1 := xor(pointB[1:17], derivedHalf1[:16])
+	block.Encrypt(pointBX1, pointBX1)
+	pointBX2 := xor(pointB[17:33], derivedHalf1[16:32])
+	block.Encrypt(pointBX2, pointBX2)
+
+	confirmation := append(append([]byte(nil), magicConfirmation...), flag)
+	confirmation = append(confirmation, addressHash...)
+	confirmation = append(confirmation, ownerEntropy...)
+	confirmation = append(confirmation, pointB[0]^(derivedHalf2[31]&0x01))
+	confirmation = append(confirmation, pointBX1...)
+	confirmation = append(confirmation, pointBX2...)
+
+	return &GeneratedKey{
+		EncryptedKey:     base58.CheckEncode(encrypted),
+		ConfirmationCode: base58.CheckEncode(confirmation),
+		Address:          p2pkhAddress(generatedKey, compressed),
+	}, nil
+}
+
+// VerifyConfirmationCode checks the confirmation code against the passphrase
+// and returns the address of the encrypted key it confirms
+func VerifyConfirmationCode(confirmationCode string, passphrase string) (string, error) {
+	payload, err := base58.CheckDecode(confirmationCode)
+	if err != nil {
+		return "", err
+	}
+	if len(payload) != confirmationCodeLength || !bytes.Equal(payload[:5], magicConfirmation) {
+		return "", errors.New("Invalid confirmation code: must be 51 bytes starting with cfrm38")
+	}
+
+	flag := payload[5]
+	compressed := flag&flagCompressed != 0
+	addressHash := payload[6:10]
+	ownerEntropy := payload[10:18]
+
+	passFactor, err := passFactor(passphrase, ownerEntropy, flag&flagLotSequence != 0)
+	if err != nil {
+		return "", err
+	}
+	derivedHalf1, derivedHalf2, err := deriveECMultiplied(passPoint(passFactor), addressHash, ownerEntropy)
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(derivedHalf2)
+	if err != nil {
+		return "", err
+	}
+
+	pointB := make([]byte, 33)
+	pointB[0] = payload[18] ^ (derivedHalf2[31] & 0x01)
+	block.Decrypt(pointB[1:17], payload[19:35])
+	block.Decrypt(pointB[17:33], payload[35:51])
+	copy(pointB[1:], xor(pointB[1:], derivedHalf1))
+
+	parsedPointB, err := btcec.ParsePubKey(pointB)
+	if err != nil {
+		return "", ErrInvalidPassphrase
+	}
+	generatedKey, err := multiply(parsedPointB, passFactor)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(addressHash, addressHashOf(generatedKey, compressed)) {
+		return "", ErrInvalidPassphrase
+	}
+	return p2pkhAddress(generatedKey, compressed), nil
+}
+
+func decryptECMultiplied(payload []byte, passphrase string) (*key.PrivateKey, error) {
+	flag := payload[2]
+	addressHash := payload[3:7]
+	ownerEntropy := payload[7:15]
+
+	passFactor, err := passFactor(passphrase, ownerEntropy, flag&flagLotSequence != 0)
+	if err != nil {
+		return nil, err
+	}
+	derivedHalf1, derivedHalf2, err := deriveECMultiplied(passPoint(passFactor), addressHash, ownerEntropy)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(derivedHalf2)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted2 := make([]byte, 16)
+	block.Decrypt(decrypted2, payload[23:39])
+	decrypted2 = xor(decrypted2, derivedHalf1[16:32])
+
+	encryptedPart1 := append(append([]byte(nil), payload[15:23]...), decrypted2[:8]...)
+	decrypted1 := make([]byte, 16)
+	block.Decrypt(decrypted1, encryptedPart1)
+	seedB := append(xor(decrypted1, derivedHalf1[:16]), decrypted2[8:16]...)
+
+	d := new(big.Int).SetBytes(passFactor)
+	d.Mul(d, new(big.Int).SetBytes(doubleSHA256(seedB)))
+	d.Mod(d, btcec.S256().N)
+	if d.Sign() == 0 {
+		return nil, ErrInvalidPassphrase
+	}
+	return key.PrivateKeyFromByte(d.FillBytes(make([]byte, 32)))
+}
+
+// passFactor derives the scalar of the passphrase from the owner entropy.
+// With lot and sequence only the first 4 bytes are the owner salt.
+func passFactor(passphrase string, ownerEntropy []byte, lotSequence bool) ([]byte, error) {
+	ownerSalt := ownerEntropy
+	if lotSequence {
+		ownerSalt = ownerEntropy[:4]
+	}
+	preFactor, err := scrypt.Key(normalize(passphrase), ownerSalt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	if !lotSequence {
+		return preFactor, nil
+	}
+	return doubleSHA256(append(preFactor, ownerEntropy...)), nil
+}
+
+// passPoint returns the compressed point scalar * G
+func passPoint(scalar []byte) []byte {
+	privateKey, _ := btcec.PrivKeyFromBytes(scalar)
+	return privateKey.PubKey().SerializeCompressed()
+}
+
+// deriveECMultiplied derives the AES key halves from the passpoint
+func deriveECMultiplied(passPoint []byte, addressHash []byte, ownerEntropy []byte) ([]byte, []byte, error) {
+	salt := append(append([]byte(nil), addressHash...), ownerEntropy...)
+	derived, err := scrypt.Key(passPoint, salt, 1024, 1, 1, 64)
+	if err != nil {
+		return nil, nil, err
+	}
+	return derived[:32], derived[32:], nil
+}
+
+// multiply returns scalar * point as a public key
+func multiply(point *btcec.PublicKey, scalar []byte) (*key.PublicKey, error) {
+	curve := btcec.S256()
+	x, y := curve.ScalarMult(point.X(), point.Y(), scalar)
+	return key.NewPublicKey(x, y)
+}
@@ -0,0 +1,340 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/03-address/address"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	version = 3
+
+	// StandardScryptN and StandardScryptP are the scrypt parameters used by geth
+	StandardScryptN = 1 << 18
+	StandardScryptP = 1
+	// LightScryptN and LightScryptP use about 4MB of memory and 100ms of CPU
+	LightScryptN = 1 << 12
+	LightScryptP = 6
+	// StandardPBKDF2Iterations is the PBKDF2-HMAC-SHA256 iteration count used by geth and MetaMask
+	StandardPBKDF2Iterations = 262144
+
+	scryptR          = 8
+	scryptKDF        = "scrypt"
+	pbkdf2KDF        = "pbkdf2"
+	pbkdf2PRF        = "hmac-sha256"
+	cipherName       = "aes-128-ctr"
+	derivedKeyLength = 32
+)
+
+var ErrInvalidPassphrase = errors.New("Invalid passphrase: MAC mismatch, could not decrypt key")
+
+// KeyJSON is the Web3 Secret Storage v3 file format
+type KeyJSON struct {
+	Address string     `json:"address,omitempty"`
+	Crypto  CryptoJSON `json:"crypto"`
+	ID      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+// CryptoJSON holds the encrypted data with its KDF and cipher parameters
+type CryptoJSON struct {
+	Cipher       string           `json:"cipher"`
+	CipherText   string           `json:"ciphertext"`
+	CipherParams CipherParamsJSON `json:"cipherparams"`
+	KDF          string           `json:"kdf"`
+	KDFParams    KDFParamsJSON    `json:"kdfparams"`
+	MAC          string           `json:"mac"`
+}
+
+type CipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// KDFParamsJSON holds the parameters of scrypt (n, r, p) or PBKDF2 (c, prf)
+type KDFParamsJSON struct {
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+	N     int    `json:"n,omitempty"`
+	R     int    `json:"r,omitempty"`
+	P     int    `json:"p,omitempty"`
+	C     int    `json:"c,omitempty"`
+	PRF   string `json:"prf,omitempty"`
+}
+
+// EncryptKey encrypts the private key with scrypt and AES-128-CTR
+func EncryptKey(privateKey *key.PrivateKey, passphrase string, scryptN, scryptP int) ([]byte, error) {
+	salt, err := randomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+	kdfParams := KDFParamsJSON{DKLen: derivedKeyLength, Salt: hex.EncodeToString(salt), N: scryptN, R: scryptR, P: scryptP}
+	return encryptKey(privateKey, passphrase, scryptKDF, kdfParams)
+}
+
+// EncryptKeyPBKDF2 encrypts the private key with PBKDF2-HMAC-SHA256 and AES-128-CTR
+func EncryptKeyPBKDF2(privateKey *key.PrivateKey, passphrase string, iterations int) ([]byte, error) {
+	salt, err := randomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+	kdfParams := KDFParamsJSON{DKLen: derivedKeyLength, Salt: hex.EncodeToString(salt), C: iterations, PRF: pbkdf2PRF}
+	return encryptKey(privateKey, passphrase, pbkdf2KDF, kdfParams)
+}
+
+func encryptKey(privateKey *key.PrivateKey, passphrase string, kdf string, kdfParams KDFParamsJSON) ([]byte, error) {
+	cryptoJSON, err := encryptData(privateKey.Serialize(), passphrase, kdf, kdfParams)
+	if err != nil {
+		return nil, err
+	}
+	keyAddress, err := addressHex(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(KeyJSON{
+		Address: keyAddress,
+		Crypto:  *cryptoJSON,
+		ID:      id,
+		Version: version,
+	})
+}
+
+// DecryptKey decrypts a key file produced by geth, MetaMask or EncryptKey.
+// The address field, when present, must match the decrypted key.
+func DecryptKey(keyJSON []byte, passphrase string) (*key.PrivateKey, error) {
+	var k KeyJSON
+	err := json.Unmarshal(keyJSON, &k)
+	if err != nil {
+		return nil, err
+	}
+	if k.Version != version {
+		return nil, fmt.Errorf("Invalid keystore version: %d, must be %d", k.Version, version)
+	}
+
+	plainText, err := DecryptData(k.Crypto, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(plainText) > 32 {
+		return nil, errors.New("Invalid private key length: must be at most 32 bytes")
+	}
+
+	// early geth versions stored keys with leading zero bytes stripped
+	privateKeyBytes := make([]byte, 32)
+	copy(privateKeyBytes[32-len(plainText):], plainText)
+	privateKey, err := key.PrivateKeyFromByte(privateKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if k.Address != "" {
+		keyAddress, err := addressHex(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(strings.TrimPrefix(k.Address, "0x"), keyAddress) {
+			return nil, errors.New("Invalid key file: address does not match the decrypted key")
+		}
+	}
+	return privateKey, nil
+}
+
+// EncryptSeed encrypts an HD wallet seed with scrypt and AES-128-CTR.
+// The file has no address since a seed backs many accounts.
+func EncryptSeed(seed []byte, passphrase string, scryptN, scryptP int) ([]byte, error) {
+	salt, err := randomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+	kdfParams := KDFParamsJSON{DKLen: derivedKeyLength, Salt: hex.EncodeToString(salt), N: scryptN, R: scryptR, P: scryptP}
+	cryptoJSON, err := encryptData(seed, passphrase, scryptKDF, kdfParams)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(KeyJSON{Crypto: *cryptoJSON, ID: id, Version: version})
+}
+
+// DecryptSeed decrypts a seed encrypted with EncryptSeed
+func DecryptSeed(seedJSON []byte, passphrase string) ([]byte, error) {
+	var k KeyJSON
+	err := json.Unmarshal(seedJSON, &k)
+	if err != nil {
+		return nil, err
+	}
+	if k.Version != version {
+		return nil, fmt.Errorf("Invalid keystore version: %d, must be %d", k.Version, version)
+	}
+	return DecryptData(k.Crypto, passphrase)
+}
+
+// StoreKey encrypts the private key and writes it to dir with the
+// UTC--<time>--<address> file name used by geth, readable only by the owner
+func StoreKey(dir string, privateKey *key.PrivateKey, passphrase string, scryptN, scryptP int) (string, error) {
+	keyJSON, err := EncryptKey(privateKey, passphrase, scryptN, scryptP)
+	if err != nil {
+		return "", err
+	}
+	keyAddress, err := addressHex(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", err
+	}
+	timestamp := strings.ReplaceAll(time.Now().UTC().Format("2006-01-02T15:04:05.999999999Z07:00"), ":", "-")
+	path := filepath.Join(dir, "UTC--"+timestamp+"--"+keyAddress)
+	return path, os.WriteFile(path, keyJSON, 0600)
+}
+
+func encryptData(data []byte, passphrase string, kdf string, kdfParams KDFParamsJSON) (*CryptoJSON, error) {
+	derivedKey, err := deriveKey(passphrase, kdf, kdfParams)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := aesCTR(derivedKey[:16], iv, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CryptoJSON{
+		Cipher:       cipherName,
+		CipherText:   hex.EncodeToString(cipherText),
+		CipherParams: CipherParamsJSON{IV: hex.EncodeToString(iv)},
+		KDF:          kdf,
+		KDFParams:    kdfParams,
+		MAC:          hex.EncodeToString(mac(derivedKey, cipherText)),
+	}, nil
+}
+
+// DecryptData verifies the MAC and decrypts the cipher text
+func DecryptData(cryptoJSON CryptoJSON, passphrase string) ([]byte, error) {
+	if cryptoJSON.Cipher != cipherName {
+		return nil, errors.New("Unsupported cipher: " + cryptoJSON.Cipher)
+	}
+	cipherText, err := hex.DecodeString(cryptoJSON.CipherText)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(cryptoJSON.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+	wantMAC, err := hex.DecodeString(cryptoJSON.MAC)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKey, err := deriveKey(passphrase, cryptoJSON.KDF, cryptoJSON.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(mac(derivedKey, cipherText), wantMAC) {
+		return nil, ErrInvalidPassphrase
+	}
+	return aesCTR(derivedKey[:16], iv, cipherText)
+}
+
+func deriveKey(passphrase string, kdf string, params KDFParamsJSON) ([]byte, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if params.DKLen < derivedKeyLength {
+		return nil, errors.New("Invalid KDF parameter: dklen must be at least 32")
+	}
+
+	switch kdf {
+	case scryptKDF:
+		return scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	case pbkdf2KDF:
+		if params.PRF != pbkdf2PRF {
+			return nil, errors.New("Unsupported PBKDF2 PRF: " + params.PRF)
+		}
+		if params.C <= 0 {
+			return nil, errors.New("Invalid KDF parameter: c must be positive")
+		}
+		return pbkdf2.Key([]byte(passphrase), salt, params.C, params.DKLen, sha256.New), nil
+	}
+	return nil, errors.New("Unsupported KDF: " + kdf)
+}
+
+// mac returns Keccak-256(derivedKey[16:32] || cipherText)
+func mac(derivedKey []byte, cipherText []byte) []byte {
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write(derivedKey[16:32])
+	hasher.Write(cipherText)
+	return hasher.Sum(nil)
+}
+
+func aesCTR(key []byte, iv []byte, input []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, errors.New("Invalid IV length: must be 16 bytes")
+	}
+	output := make([]byte, len(input))
+	cipher.NewCTR(block, iv).XORKeyStream(output, input)
+	return output, nil
+}
+
+// addressHex returns the lowercase Ethereum address without 0x as stored in key files
+func addressHex(privateKey *key.PrivateKey) (string, error) {
+	eip55Address, err := address.ToEIP55Address(privateKey.PublicKey())
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(eip55Address[2:]), nil
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() (string, error) {
+	u, err := randomBytes(16)
+	if err != nil {
+		return "", err
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
+
+func randomBytes(length int) ([]byte, error) {
+	b := make([]byte, length)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,151 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boxwood-zip/learning-blockchain/hdwallet/02-key_derivation/key"
+)
+
+// keystoreVectors are the Web3 Secret Storage test vectors and key files written by geth
+var keystoreVectors = []struct {
+	name       string
+	json       string
+	passphrase string
+	privateKey string
+}{
+	{
+		"scrypt",
+		`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+		"testpassword",
+		"7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d",
+	},
+	{
+		"pbkdf2",
+		`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+		"testpassword",
+		"7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d",
+	},
+	{
+		"geth key file",
+		`{"address":"7ef5a6135f1fd6a02593eedc869c6d41d934aef8","crypto":{"cipher":"aes-128-ctr","ciphertext":"1d0839166e7a15b9c1333fc865d69858b22df26815ccf601b28219b6192974e1","cipherparams":{"iv":"8df6caa7ff1b00c4e871f002cb7921ed"},"kdf":"scrypt","kdfparams":{"dklen":32,"n":8,"p":16,"r":8,"salt":"e5e6ef3f4ea695f496b643ebd3f75c0aa58ef4070e90c80c5d3fb0241bf1595c"},"mac":"6d16dfde774845e4585357f24bce530528bc69f4f84e1e22880d34fa45c273e5"},"id":"950077c7-71e3-4c44-a4a1-143919141ed4","version":3}`,
+		"foobar",
+		"",
+	},
+	{
+		"31 byte key",
+		`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"e0c41130a323adc1446fc82f724bca2f"},"ciphertext":"9517cd5bdbe69076f9bf5057248c6c050141e970efa36ce53692d5d59a3984","kdf":"scrypt","kdfparams":{"dklen":32,"n":2,"r":8,"p":1,"salt":"711f816911c92d649fb4c84b047915679933555030b3552c1212609b38208c63"},"mac":"d5e116151c6aa71470e67a7d42c9620c75c4d23229847dcc127794f0732b0db5"},"id":"fecfc4ce-e956-48fd-953b-30f8b52ed66c","version":3}`,
+		"foo",
+		"00fa7b3db73dc7dfdf8c5fbdb796d741e4488628c41fc4febd9160a866ba0f35",
+	},
+}
+
+func TestDecryptKeyVectors(t *testing.T) {
+	for _, v := range keystoreVectors {
+		privateKey, err := DecryptKey([]byte(v.json), v.passphrase)
+		if err != nil {
+			t.Fatalf("%s: %v", v.name, err)
+		}
+		if v.privateKey != "" && privateKey.Hex() != "0x"+v.privateKey {
+			t.Fatalf("%s: got %s, want 0x%s", v.name, privateKey.Hex(), v.privateKey)
+		}
+
+		_, err = DecryptKey([]byte(v.json), v.passphrase+"x")
+		if err != ErrInvalidPassphrase {
+			t.Fatalf("%s: wrong passphrase: got %v, want %v", v.name, err, ErrInvalidPassphrase)
+		}
+	}
+}
+
+func TestEncryptKey(t *testing.T) {
+	privateKeyBytes, _ := hex.DecodeString("7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d")
+	privateKey, _ := key.PrivateKeyFromByte(privateKeyBytes)
+
+	scryptJSON, err := EncryptKey(privateKey, "testpassword", LightScryptN, LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pbkdf2JSON, err := EncryptKeyPBKDF2(privateKey, "testpassword", 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, keyJSON := range [][]byte{scryptJSON, pbkdf2JSON} {
+		var k KeyJSON
+		json.Unmarshal(keyJSON, &k)
+		if k.Version != 3 || k.Crypto.Cipher != "aes-128-ctr" || len(k.ID) != 36 {
+			t.Fatalf("unexpected key file %s", keyJSON)
+		}
+		if k.Address != "008aeeda4d805471df9b2a5b0f38a0c3bcba786b" {
+			t.Fatalf("got address %s", k.Address)
+		}
+
+		decrypted, err := DecryptKey(keyJSON, "testpassword")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decrypted.Hex() != privateKey.Hex() {
+			t.Fatalf("got %s, want %s", decrypted.Hex(), privateKey.Hex())
+		}
+	}
+
+	// a key file whose address was edited must not be trusted
+	tampered := strings.Replace(string(scryptJSON), "008aeeda", "108aeeda", 1)
+	_, err = DecryptKey([]byte(tampered), "testpassword")
+	if err == nil {
+		t.Fatal("mismatching address: expected error")
+	}
+}
+
+func TestEncryptSeed(t *testing.T) {
+	seed, _ := hex.DecodeString("5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4")
+
+	seedJSON, err := EncryptSeed(seed, "passphrase", LightScryptN, LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := DecryptSeed(seedJSON, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(decrypted) != hex.EncodeToString(seed) {
+		t.Fatalf("got %x, want %x", decrypted, seed)
+	}
+
+	_, err = DecryptSeed(seedJSON, "wrong")
+	if err != ErrInvalidPassphrase {
+		t.Fatalf("got %v, want %v", err, ErrInvalidPassphrase)
+	}
+}
+
+func TestStoreKey(t *testing.T) {
+	privateKeyBytes, _ := hex.DecodeString("7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d")
+	privateKey, _ := key.PrivateKeyFromByte(privateKeyBytes)
+
+	dir := t.TempDir()
+	path, err := StoreKey(dir, privateKey, "foobar", LightScryptN, LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Base(path)
+	if !strings.HasPrefix(name, "UTC--") || !strings.HasSuffix(name, "--008aeeda4d805471df9b2a5b0f38a0c3bcba786b") {
+		t.Fatalf("unexpected key file name %s", name)
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("got permissions %v, want 0600", info.Mode().Perm())
+	}
+	keyJSON, _ := os.ReadFile(path)
+	decrypted, err := DecryptKey(keyJSON, "foobar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted.Hex() != privateKey.Hex() {
+		t.Fatalf("got %s, want %s", decrypted.Hex(), privateKey.Hex())
+	}
+}